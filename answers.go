@@ -0,0 +1,160 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	answersFileName = ".scaffold-answers.toml"
+)
+
+// Answers records the template a project was generated from and the values
+// supplied for its input tokens, so the project can be regenerated later.
+type Answers struct {
+	Template string            `toml:"template" json:"template"`
+	Version  string            `toml:"version,omitempty" json:"version,omitempty"`
+	Values   map[string]string `toml:"values" json:"values"`
+}
+
+// ReadAnswers reads an answers file in TOML, JSON or YAML format, chosen by
+// the file extension. The file is either a recorded answers file, with token
+// values in a values table, or a flat mapping of token names to values. Null
+// values are left out.
+func ReadAnswers(path string) (Answers, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Answers{}, err
+	}
+
+	document := make(map[string]any)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(content, &document)
+	case ".json":
+		err = json.Unmarshal(content, &document)
+	case ".yaml", ".yml":
+		document, err = decodeYAML(content)
+	default:
+		return Answers{}, fmt.Errorf("unsupported answers file format %q", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return Answers{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	answers := Answers{Values: make(map[string]string)}
+
+	values := document
+	if recorded, ok := document["values"].(map[string]any); ok {
+		values = recorded
+		answers.Template, _ = document["template"].(string)
+		answers.Version, _ = document["version"].(string)
+	}
+
+	for name, value := range values {
+		// A null answer leaves the token without a value
+		if value == nil {
+			continue
+		}
+
+		answer, err := answerValue(value)
+		if err != nil {
+			return Answers{}, fmt.Errorf("%s: token %q: %w", path, name, err)
+		}
+
+		answers.Values[name] = answer
+	}
+
+	return answers, nil
+}
+
+// LoadAnswers registers every value from an answers file.
+func (scaf *Scaffold) LoadAnswers(path string) error {
+	answers, err := ReadAnswers(path)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range answers.Values {
+		scaf.RegisterTokenValue(name, value)
+	}
+
+	return nil
+}
+
+// answers returns the record written into the destination, holding the
+// resolved value of every input token. tokens must be in config order.
+func (scaf *Scaffold) answers(tokens []Token) Answers {
+	answers := Answers{
+		Template: scaf.Path,
+		Version:  scaf.Config.Version,
+		Values:   make(map[string]string),
+	}
+
+	for i, token := range scaf.Config.Tokens {
+		if _, ok := answers.Values[token.Name]; ok || !token.IsInput() {
+			continue
+		}
+
+		answers.Values[token.Name] = tokens[i].input
+	}
+
+	return answers
+}
+
+// answerValue converts a decoded answer into the string form token values
+// are registered with. Lists are joined with commas.
+func answerValue(value any) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case bool, int64, float64, int:
+		return fmt.Sprint(value), nil
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			converted, err := answerValue(item)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, converted)
+		}
+
+		return strings.Join(items, ","), nil
+	}
+
+	return "", fmt.Errorf("unsupported value %v", value)
+}
+
+// decodeYAML decodes a YAML answers file, which must hold a single document
+// rather than have every document after the first ignored.
+func decodeYAML(content []byte) (map[string]any, error) {
+	document := make(map[string]any)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	if err := decoder.Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var next any
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("expected a single YAML document")
+	}
+
+	return document, nil
+}
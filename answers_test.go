@@ -0,0 +1,208 @@
+package scaffold
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadAnswers(t *testing.T) {
+	files := map[string]string{
+		"answers.toml": `
+			"{{name}}" = "billing"
+			db = "postgres"
+			features = ["grpc", "http"]
+			replicas = 3
+		`,
+		"answers.json": `{
+			"{{name}}": "billing",
+			"db": "postgres",
+			"features": ["grpc", "http"],
+			"replicas": 3
+		}`,
+		"answers.yaml": `
+# answers for the billing service
+"{{name}}": billing
+db: 'postgres' # default
+features:
+  - grpc
+  - "http"
+replicas: 3
+`,
+		"recorded.yml": `
+template: templates/service
+version: "1.2.0"
+values:
+  "{{name}}": billing
+  db: postgres
+  features: [grpc, http]
+  replicas: 3
+`,
+	}
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		err := os.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+
+		answers, err := ReadAnswers(path)
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", name, err)
+			continue
+		}
+
+		expected := map[string]string{
+			"{{name}}": "billing",
+			"db":       "postgres",
+			"features": "grpc,http",
+			"replicas": "3",
+		}
+
+		if len(answers.Values) != len(expected) {
+			t.Errorf("%s: expected %d values, got %v", name, len(expected), answers.Values)
+		}
+
+		for token, value := range expected {
+			if answers.Values[token] != value {
+				t.Errorf("%s: unexpected value for %s. Expected %q, got %q", name, token, value, answers.Values[token])
+			}
+		}
+	}
+
+	recorded, _ := ReadAnswers(filepath.Join(dir, "recorded.yml"))
+	if recorded.Template != "templates/service" || recorded.Version != "1.2.0" {
+		t.Errorf("Unexpected recorded template: %+v", recorded)
+	}
+}
+
+func TestReadYAMLAnswers(t *testing.T) {
+	dir := t.TempDir()
+
+	read := func(content string) (Answers, error) {
+		path := filepath.Join(dir, "answers.yaml")
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write answers: %v", err)
+		}
+
+		return ReadAnswers(path)
+	}
+
+	answers, err := read(`
+"{{host:port}}": "localhost:8080"
+db: &db postgres
+replica: *db
+description: |
+  Billing service
+owner: null
+team: ~
+`)
+	if err != nil {
+		t.Fatalf("Failed to read answers: %v", err)
+	}
+
+	expected := map[string]string{
+		"{{host:port}}": "localhost:8080",
+		"db":            "postgres",
+		"replica":       "postgres",
+		"description":   "Billing service\n",
+	}
+
+	if !maps.Equal(answers.Values, expected) {
+		t.Errorf("Unexpected answers. Expected %v, got %v", expected, answers.Values)
+	}
+
+	invalid := map[string]string{
+		"mapping value":        "features: {grpc: true}\n",
+		"nested mapping value": "name: a: b\n",
+		"several documents":    "name: billing\n---\nname: orders\n",
+	}
+
+	for name, content := range invalid {
+		if _, err := read(content); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMakeRecordsAnswers(t *testing.T) {
+	configContent := `
+		version = "1.2.0"
+
+		[[token]]
+		name = "{{name}}"
+
+		[[token]]
+		name = "{{Name}}"
+		token = "{{name}}"
+		modifiers = ["pascal"]
+
+		[[token]]
+		name = "{{db}}"
+		default = "postgres"
+
+		[[token]]
+		name = "{{static}}"
+		value = "static"
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"main.go": "type {{Name}} struct{} // {{db}} {{static}}",
+	})
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.RegisterTokenValue("{{name}}", "billing")
+
+	destDir := t.TempDir()
+
+	if err := scaf.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	answers, err := ReadAnswers(filepath.Join(destDir, answersFileName))
+	if err != nil {
+		t.Fatalf("Failed to read recorded answers: %v", err)
+	}
+
+	if answers.Template != templateDir || answers.Version != "1.2.0" {
+		t.Errorf("Unexpected recorded template: %+v", answers)
+	}
+
+	if len(answers.Values) != 2 || answers.Values["{{name}}"] != "billing" || answers.Values["{{db}}"] != "postgres" {
+		t.Errorf("Unexpected recorded values: %v", answers.Values)
+	}
+
+	// Regenerating from the recorded answers reproduces the project
+	regenerated, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	if err := regenerated.LoadAnswers(filepath.Join(destDir, answersFileName)); err != nil {
+		t.Fatalf("Failed to load answers: %v", err)
+	}
+
+	regeneratedDir := t.TempDir()
+
+	if err := regenerated.Make(regeneratedDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	for _, name := range []string{"main.go", answersFileName} {
+		original, _ := os.ReadFile(filepath.Join(destDir, name))
+		copied, _ := os.ReadFile(filepath.Join(regeneratedDir, name))
+
+		if string(original) != string(copied) {
+			t.Errorf("Regenerated %s differs.\nOriginal:\n%s\nRegenerated:\n%s", name, original, copied)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package scaffold
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// archiveEntries remembers which names were already written to a streaming
+// archive so Exists can answer without reading the archive back.
+type archiveEntries map[string]bool
+
+func (entries archiveEntries) Exists(name string) (bool, error) {
+	name = path.Clean(name)
+
+	return name == "." || entries[name], nil
+}
+
+// ZipOutput streams generated files into a zip archive. Close must be called
+// to write the archive's central directory.
+type ZipOutput struct {
+	archiveEntries
+	writer *zip.Writer
+}
+
+func NewZipOutput(w io.Writer) *ZipOutput {
+	return &ZipOutput{
+		archiveEntries: make(archiveEntries),
+		writer:         zip.NewWriter(w),
+	}
+}
+
+func (out *ZipOutput) MkdirAll(name string, perm fs.FileMode) error {
+	name = path.Clean(name)
+	if exists, _ := out.Exists(name); exists {
+		return nil
+	}
+
+	if err := out.MkdirAll(path.Dir(name), perm); err != nil {
+		return err
+	}
+
+	header := &zip.FileHeader{Name: name + "/", Modified: time.Now()}
+	header.SetMode(fs.ModeDir | perm)
+
+	if _, err := out.writer.CreateHeader(header); err != nil {
+		return err
+	}
+
+	out.archiveEntries[name] = true
+
+	return nil
+}
+
+func (out *ZipOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	name = path.Clean(name)
+
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()}
+	header.SetMode(perm)
+
+	w, err := out.writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	out.archiveEntries[name] = true
+
+	return nil
+}
+
+func (out *ZipOutput) Close() error {
+	return out.writer.Close()
+}
+
+// TarGzOutput streams generated files into a gzip compressed tar archive.
+// Close must be called to flush both the tar and gzip streams.
+type TarGzOutput struct {
+	archiveEntries
+	gzipWriter *gzip.Writer
+	writer     *tar.Writer
+}
+
+func NewTarGzOutput(w io.Writer) *TarGzOutput {
+	gzipWriter := gzip.NewWriter(w)
+
+	return &TarGzOutput{
+		archiveEntries: make(archiveEntries),
+		gzipWriter:     gzipWriter,
+		writer:         tar.NewWriter(gzipWriter),
+	}
+}
+
+func (out *TarGzOutput) MkdirAll(name string, perm fs.FileMode) error {
+	name = path.Clean(name)
+	if exists, _ := out.Exists(name); exists {
+		return nil
+	}
+
+	if err := out.MkdirAll(path.Dir(name), perm); err != nil {
+		return err
+	}
+
+	if err := out.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     int64(perm.Perm()),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	out.archiveEntries[name] = true
+
+	return nil
+}
+
+func (out *TarGzOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	name = path.Clean(name)
+
+	if err := out.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(perm.Perm()),
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := out.writer.Write(data); err != nil {
+		return err
+	}
+
+	out.archiveEntries[name] = true
+
+	return nil
+}
+
+func (out *TarGzOutput) Close() error {
+	if err := out.writer.Close(); err != nil {
+		return err
+	}
+
+	return out.gzipWriter.Close()
+}
@@ -0,0 +1,406 @@
+// Command scaffold generates projects from scaffold templates.
+//
+// Usage:
+//
+//	scaffold make <template> <destination> [--answers file] [--set name=value]... [--conflict policy] [--dry-run] [--json]
+//	scaffold inspect <template> [--json]
+//	scaffold validate <template> [--answers file] [--set name=value]... [--json]
+//
+// The exit code is 0 on success, 1 when the template or its values are
+// invalid or generation fails, and 2 for usage errors.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/trevorak/scaffold/v2"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `Usage:
+  scaffold make <template> <destination> [--answers file] [--set name=value]... [--conflict policy] [--dry-run] [--json]
+  scaffold inspect <template> [--json]
+  scaffold validate <template> [--answers file] [--set name=value]... [--json]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "make":
+		return runMake(args[1:], stdout, stderr)
+	case "inspect":
+		return runInspect(args[1:], stdout, stderr)
+	case "validate":
+		return runValidate(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+
+	return exitUsage
+}
+
+// setFlag collects repeated --set name=value flags.
+type setFlag map[string]string
+
+func (values setFlag) String() string {
+	pairs := make([]string, 0, len(values))
+	for name, value := range values {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (values setFlag) Set(pair string) error {
+	name, value, ok := strings.Cut(pair, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", pair)
+	}
+
+	values[name] = value
+
+	return nil
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments and returns the positional arguments.
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+
+	return flags
+}
+
+func runMake(args []string, stdout io.Writer, stderr io.Writer) int {
+	values := setFlag{}
+
+	flags := newFlagSet("make", stderr)
+	flags.Var(values, "set", "token value as name=value, may be repeated")
+	answers := flags.String("answers", "", "TOML, JSON or YAML file of token values")
+	conflict := flags.String("conflict", string(scaffold.ConflictOverwrite), "policy for existing files: fail, skip, overwrite or new")
+	dryRun := flags.Bool("dry-run", false, "only print the planned operations")
+	jsonOutput := flags.Bool("json", false, "print machine-readable JSON")
+
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(positional) != 2 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	scaf, err := loadTemplate(positional[0], *answers, values)
+	if err != nil {
+		return fail(stdout, stderr, *jsonOutput, err)
+	}
+
+	if err := scaf.SetConflictPolicy(scaffold.ConflictPolicy(*conflict)); err != nil || *conflict == string(scaffold.ConflictPrompt) {
+		fmt.Fprintf(stderr, "invalid conflict policy %q\n", *conflict)
+		return exitUsage
+	}
+
+	operations, err := scaf.Plan(positional[1])
+	if err != nil {
+		return fail(stdout, stderr, *jsonOutput, err)
+	}
+
+	if !*dryRun {
+		if err := scaf.Make(positional[1]); err != nil {
+			return fail(stdout, stderr, *jsonOutput, err)
+		}
+	}
+
+	if *jsonOutput {
+		return writeJSON(stdout, stderr, map[string]any{
+			"dry_run":    *dryRun,
+			"operations": operations,
+		})
+	}
+
+	for _, operation := range operations {
+		fmt.Fprintf(stdout, "%-9s %s\n", operation.Type, operation.Path)
+	}
+
+	return exitOK
+}
+
+type tokenInfo struct {
+	Name         string   `json:"name"`
+	Token        string   `json:"token,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Value        string   `json:"value,omitempty"`
+	Default      string   `json:"default,omitempty"`
+	Modifiers    []string `json:"modifiers,omitempty"`
+	Localize     []string `json:"localize,omitempty"`
+	Priority     int      `json:"priority,omitempty"`
+	Match        string   `json:"match,omitempty"`
+	Variants     bool     `json:"variants,omitempty"`
+	PreserveCase bool     `json:"preserve_case,omitempty"`
+	Required     bool     `json:"required"`
+	Input        bool     `json:"input"`
+	Description  string   `json:"description,omitempty"`
+	Prompt       string   `json:"prompt,omitempty"`
+	Example      string   `json:"example,omitempty"`
+	Group        string   `json:"group,omitempty"`
+	Pattern      string   `json:"pattern,omitempty"`
+	Choices      []string `json:"choices,omitempty"`
+}
+
+func runInspect(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("inspect", stderr)
+	jsonOutput := flags.Bool("json", false, "print machine-readable JSON")
+
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(positional) != 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	scaf, err := scaffold.Init(positional[0])
+	if err != nil {
+		return fail(stdout, stderr, *jsonOutput, err)
+	}
+
+	var tokens []tokenInfo
+	for _, token := range scaf.GetTokens() {
+		tokens = append(tokens, tokenInfo{
+			Name:         token.Name,
+			Token:        token.Token,
+			Type:         token.Type,
+			Value:        token.Value,
+			Default:      token.Default,
+			Modifiers:    token.Modifiers,
+			Localize:     token.Localize,
+			Priority:     token.Priority,
+			Match:        token.Match,
+			Variants:     token.Variants,
+			PreserveCase: token.PreserveCase,
+			Required:     token.IsRequired(),
+			Input:        token.IsInput(),
+			Description:  token.Description,
+			Prompt:       token.Prompt,
+			Example:      token.Example,
+			Group:        token.Group,
+			Pattern:      token.Pattern,
+			Choices:      token.Choices,
+		})
+	}
+
+	if *jsonOutput {
+		return writeJSON(stdout, stderr, map[string]any{
+			"template": positional[0],
+			"tokens":   tokens,
+		})
+	}
+
+	table := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tBOUND TO\tMODIFIERS\tLOCALIZE\tVALUE\tREQUIRED")
+
+	for _, token := range tokens {
+		value := token.Value
+		if value == "" && token.Default != "" {
+			value = token.Default + " (default)"
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%t\n",
+			token.Name,
+			orDash(token.Token),
+			orDash(strings.Join(token.Modifiers, ",")),
+			orDash(strings.Join(token.Localize, ",")),
+			orDash(value),
+			token.Required,
+		)
+	}
+
+	if err := table.Flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+type problem struct {
+	Token string `json:"token,omitempty"`
+	Error string `json:"error"`
+}
+
+func runValidate(args []string, stdout io.Writer, stderr io.Writer) int {
+	values := setFlag{}
+
+	flags := newFlagSet("validate", stderr)
+	flags.Var(values, "set", "token value as name=value, may be repeated")
+	answers := flags.String("answers", "", "TOML, JSON or YAML file of token values")
+	jsonOutput := flags.Bool("json", false, "print machine-readable JSON")
+
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(positional) != 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	scaf, err := loadTemplate(positional[0], *answers, values)
+	if err != nil {
+		return fail(stdout, stderr, *jsonOutput, err)
+	}
+
+	found := problems(scaf.Validate())
+
+	if *jsonOutput {
+		code := writeJSON(stdout, stderr, map[string]any{
+			"valid":    len(found) == 0,
+			"problems": found,
+		})
+		if code == exitOK && len(found) > 0 {
+			return exitError
+		}
+
+		return code
+	}
+
+	if len(found) == 0 {
+		fmt.Fprintln(stdout, "template is valid")
+		return exitOK
+	}
+
+	for _, problem := range found {
+		fmt.Fprintln(stderr, problem.Error)
+	}
+
+	return exitError
+}
+
+// loadTemplate initializes the template and registers the values from the
+// answers file, if any, followed by the --set values.
+func loadTemplate(templatePath string, answersPath string, values setFlag) (*scaffold.Scaffold, error) {
+	scaf, err := scaffold.Init(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if answersPath != "" {
+		if err := scaf.LoadAnswers(answersPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, value := range values {
+		if _, err := scaf.GetTokenByName(name); err != nil {
+			return nil, fmt.Errorf("unknown token %q", name)
+		}
+
+		scaf.RegisterTokenValue(name, value)
+	}
+
+	return scaf, nil
+}
+
+// problems flattens the errors joined by Validate into one entry each.
+func problems(err error) []problem {
+	if err == nil {
+		return []problem{}
+	}
+
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	result := make([]problem, 0, len(errs))
+	for _, err := range errs {
+		entry := problem{Error: err.Error()}
+
+		var tokenErr *scaffold.TokenError
+		if errors.As(err, &tokenErr) {
+			entry.Token = tokenErr.Token
+		}
+
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+func fail(stdout io.Writer, stderr io.Writer, jsonOutput bool, err error) int {
+	if jsonOutput {
+		writeJSON(stdout, stderr, map[string]any{
+			"error":    err.Error(),
+			"problems": problems(err),
+		})
+	} else {
+		fmt.Fprintln(stderr, err)
+	}
+
+	return exitError
+}
+
+func writeJSON(stdout io.Writer, stderr io.Writer, value any) int {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
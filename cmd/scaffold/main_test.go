@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTemplate(t *testing.T) string {
+	t.Helper()
+
+	templateDir := t.TempDir()
+
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+		pattern = "^[a-z]+$"
+
+		[[token]]
+		name = "{{Name}}"
+		token = "{{name}}"
+		modifiers = ["pascal"]
+	`
+	err := os.WriteFile(filepath.Join(templateDir, "scaffold.toml"), []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(templateDir, "{{name}}.go"), []byte("type {{Name}} struct{}"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+
+	return templateDir
+}
+
+func TestRunMake(t *testing.T) {
+	templateDir := createTemplate(t)
+	destDir := filepath.Join(t.TempDir(), "output")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"make", templateDir, destDir, "--set", "{{name}}=billing", "--json"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+
+	var result struct {
+		Operations []struct {
+			Type string `json:"type"`
+			Path string `json:"path"`
+		} `json:"operations"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode output: %v\n%s", err, stdout.String())
+	}
+
+	if len(result.Operations) != 3 || result.Operations[1].Path != filepath.Join(destDir, "billing.go") {
+		t.Errorf("Unexpected operations: %+v", result.Operations)
+	}
+
+	generatedContent, err := os.ReadFile(filepath.Join(destDir, "billing.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	if string(generatedContent) != "type Billing struct{}" {
+		t.Errorf("Unexpected generated content: %q", string(generatedContent))
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	templateDir := createTemplate(t)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"validate", "--json", templateDir, "--set", "{{name}}=Billing"}, &stdout, &stderr)
+	if code != exitError {
+		t.Fatalf("Expected exit code %d, got %d", exitError, code)
+	}
+
+	var result struct {
+		Valid    bool `json:"valid"`
+		Problems []struct {
+			Token string `json:"token"`
+		} `json:"problems"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode output: %v\n%s", err, stdout.String())
+	}
+
+	if result.Valid || len(result.Problems) != 1 || result.Problems[0].Token != "{{name}}" {
+		t.Errorf("Unexpected validation result: %+v", result)
+	}
+
+	stdout.Reset()
+	code = run([]string{"validate", templateDir, "--set", "{{name}}=billing"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Errorf("Expected exit code %d, got %d", exitOK, code)
+	}
+}
+
+func TestRunInspect(t *testing.T) {
+	templateDir := createTemplate(t)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"inspect", templateDir}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "{{Name}}") || !strings.Contains(lines[2], "pascal") {
+		t.Errorf("Unexpected inspect output:\n%s", stdout.String())
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	for _, args := range [][]string{{}, {"unknown"}, {"make", "only-template"}, {"inspect", "--bogus"}} {
+		if code := run(args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("Expected usage exit code for %v, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package scaffold
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// condition is a parsed `when` expression. Expressions compare token values
+// with string and integer literals using ==, !=, <, <=, > and >=, test list
+// membership with in, combine them with &&, || and !, and use a token on its
+// own as a boolean.
+//
+//	transport == 'grpc' && !skip_tests && replicas > 1 && 'auth' in features
+type condition interface {
+	eval(values map[string]any) (any, error)
+}
+
+type (
+	literalCondition struct {
+		value any
+	}
+
+	tokenCondition struct {
+		name string
+	}
+
+	notCondition struct {
+		operand condition
+	}
+
+	binaryCondition struct {
+		operator string
+		left     condition
+		right    condition
+	}
+)
+
+func (cond literalCondition) eval(_ map[string]any) (any, error) {
+	return cond.value, nil
+}
+
+func (cond tokenCondition) eval(values map[string]any) (any, error) {
+	value, ok := values[cond.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown token %q", cond.name)
+	}
+
+	return value, nil
+}
+
+func (cond notCondition) eval(values map[string]any) (any, error) {
+	operand, err := cond.operand.eval(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return !truthy(operand), nil
+}
+
+func (cond binaryCondition) eval(values map[string]any) (any, error) {
+	left, err := cond.left.eval(values)
+	if err != nil {
+		return nil, err
+	}
+
+	// && and || short circuit
+	switch cond.operator {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+	}
+
+	right, err := cond.right.eval(values)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cond.operator {
+	case "&&", "||":
+		return truthy(right), nil
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		switch right := right.(type) {
+		case []string:
+			return slices.Contains(right, fmt.Sprint(left)), nil
+		case string:
+			return strings.Contains(right, fmt.Sprint(left)), nil
+		}
+
+		return nil, fmt.Errorf("in needs a list or string, got %v", right)
+	case "<", "<=", ">", ">=":
+		leftInt, leftOk := toInt(left)
+		rightInt, rightOk := toInt(right)
+		if !leftOk || !rightOk {
+			return nil, fmt.Errorf("%s needs integers, got %v and %v", cond.operator, left, right)
+		}
+
+		switch cond.operator {
+		case "<":
+			return leftInt < rightInt, nil
+		case "<=":
+			return leftInt <= rightInt, nil
+		case ">":
+			return leftInt > rightInt, nil
+		}
+
+		return leftInt >= rightInt, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %q", cond.operator)
+}
+
+// equal compares two values as integers when either side is one, as
+// booleans when either side is one, and as strings otherwise.
+func equal(left any, right any) bool {
+	_, leftIsInt := left.(int)
+	_, rightIsInt := right.(int)
+	if leftIsInt || rightIsInt {
+		leftInt, leftOk := toInt(left)
+		rightInt, rightOk := toInt(right)
+
+		return leftOk && rightOk && leftInt == rightInt
+	}
+
+	_, leftIsBool := left.(bool)
+	_, rightIsBool := right.(bool)
+	if leftIsBool || rightIsBool {
+		return truthy(left) == truthy(right)
+	}
+
+	return fmt.Sprint(left) == fmt.Sprint(right)
+}
+
+func toInt(value any) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		return parsed, err == nil
+	}
+
+	return 0, false
+}
+
+// truthy reports whether a value counts as true. Strings are true unless
+// empty or a false boolean such as "false" or "0", integers unless zero and
+// lists unless empty.
+func truthy(value any) bool {
+	switch value := value.(type) {
+	case bool:
+		return value
+	case int:
+		return value != 0
+	case []string:
+		return len(value) > 0
+	case string:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+
+		return value != ""
+	}
+
+	return value != nil
+}
+
+// included reports whether every include rule matching a template path holds.
+func (scaf *Scaffold) included(relativePath string, values map[string]any) (bool, error) {
+	for _, rule := range scaf.Config.Includes {
+		if !matchPath(rule.Path, relativePath) {
+			continue
+		}
+
+		included, err := evalCondition(rule.When, values)
+		if err != nil || !included {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// evalCondition parses and evaluates a `when` expression.
+func evalCondition(expression string, values map[string]any) (bool, error) {
+	cond, err := parseCondition(expression)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := cond.eval(values)
+	if err != nil {
+		return false, fmt.Errorf("condition %q: %w", expression, err)
+	}
+
+	return truthy(result), nil
+}
+
+type conditionToken struct {
+	kind  string
+	value string
+}
+
+type conditionParser struct {
+	tokens   []conditionToken
+	position int
+}
+
+func parseCondition(expression string) (condition, error) {
+	tokens, err := lexCondition(expression)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", expression, err)
+	}
+
+	parser := &conditionParser{tokens: tokens}
+
+	cond, err := parser.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", expression, err)
+	}
+
+	if parser.position < len(parser.tokens) {
+		return nil, fmt.Errorf("condition %q: unexpected %q", expression, parser.tokens[parser.position].value)
+	}
+
+	return cond, nil
+}
+
+func (parser *conditionParser) peek() conditionToken {
+	if parser.position < len(parser.tokens) {
+		return parser.tokens[parser.position]
+	}
+
+	return conditionToken{kind: "end"}
+}
+
+// accept consumes the next token if it is the given operator.
+func (parser *conditionParser) accept(operator string) bool {
+	if token := parser.peek(); token.kind != "operator" || token.value != operator {
+		return false
+	}
+
+	parser.position++
+
+	return true
+}
+
+func (parser *conditionParser) next() conditionToken {
+	token := parser.peek()
+	parser.position++
+
+	return token
+}
+
+func (parser *conditionParser) parseOr() (condition, error) {
+	left, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.accept("||") {
+		right, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryCondition{operator: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (parser *conditionParser) parseAnd() (condition, error) {
+	left, err := parser.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.accept("&&") {
+		right, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryCondition{operator: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (parser *conditionParser) parseUnary() (condition, error) {
+	if parser.accept("!") {
+		operand, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notCondition{operand: operand}, nil
+	}
+
+	return parser.parseComparison()
+}
+
+func (parser *conditionParser) parseComparison() (condition, error) {
+	left, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if !parser.accept(operator) {
+			continue
+		}
+
+		right, err := parser.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return binaryCondition{operator: operator, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (parser *conditionParser) parseOperand() (condition, error) {
+	token := parser.next()
+
+	switch token.kind {
+	case "string":
+		return literalCondition{value: token.value}, nil
+	case "identifier":
+		switch token.value {
+		case "true":
+			return literalCondition{value: true}, nil
+		case "false":
+			return literalCondition{value: false}, nil
+		}
+
+		if number, err := strconv.Atoi(token.value); err == nil {
+			return literalCondition{value: number}, nil
+		}
+
+		return tokenCondition{name: token.value}, nil
+	case "operator":
+		if token.value == "(" {
+			cond, err := parser.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if !parser.accept(")") {
+				return nil, fmt.Errorf("expected ) but found %q", parser.peek().value)
+			}
+
+			return cond, nil
+		}
+	case "end":
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %q", token.value)
+}
+
+// lexCondition splits an expression into operators, quoted strings and
+// identifiers. Identifiers may contain any character that is not whitespace,
+// a quote or part of an operator, so token names like {{name}} or
+// slug-token can be used as they are.
+func lexCondition(expression string) ([]conditionToken, error) {
+	var tokens []conditionToken
+
+	for i := 0; i < len(expression); {
+		switch char := expression[i]; {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case strings.HasPrefix(expression[i:], "&&"), strings.HasPrefix(expression[i:], "||"),
+			strings.HasPrefix(expression[i:], "=="), strings.HasPrefix(expression[i:], "!="),
+			strings.HasPrefix(expression[i:], "<="), strings.HasPrefix(expression[i:], ">="):
+			tokens = append(tokens, conditionToken{kind: "operator", value: expression[i : i+2]})
+			i += 2
+		case char == '!' || char == '(' || char == ')' || char == '<' || char == '>':
+			tokens = append(tokens, conditionToken{kind: "operator", value: string(char)})
+			i++
+		case char == '\'' || char == '"':
+			end := strings.IndexByte(expression[i+1:], char)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+
+			tokens = append(tokens, conditionToken{kind: "string", value: expression[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			start := i
+			for i < len(expression) && !strings.ContainsRune(" \t\n\r'\"!()&|=<>", rune(expression[i])) {
+				i++
+			}
+
+			if start == i {
+				return nil, fmt.Errorf("unexpected %q at offset %d", char, i)
+			}
+
+			kind := "identifier"
+			if expression[start:i] == "in" {
+				kind = "operator"
+			}
+
+			tokens = append(tokens, conditionToken{kind: kind, value: expression[start:i]})
+		}
+	}
+
+	return tokens, nil
+}
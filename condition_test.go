@@ -0,0 +1,131 @@
+package scaffold
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEvalCondition(t *testing.T) {
+	values := map[string]any{
+		"transport":  "grpc",
+		"with_tests": "true",
+		"skip_docs":  "false",
+		"{{name}}":   "billing",
+		"slug-token": "",
+		"replicas":   3,
+		"features":   []string{"auth", "metrics"},
+		"debug":      false,
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{expression: "transport == 'grpc'", expected: true},
+		{expression: `transport == "http"`, expected: false},
+		{expression: "transport != 'http'", expected: true},
+		{expression: "with_tests", expected: true},
+		{expression: "skip_docs", expected: false},
+		{expression: "!skip_docs", expected: true},
+		{expression: "slug-token", expected: false},
+		{expression: "{{name}} == 'billing' && with_tests", expected: true},
+		{expression: "transport == 'http' || transport == 'grpc'", expected: true},
+		{expression: "!(transport == 'grpc' && skip_docs)", expected: true},
+		{expression: "with_tests == true", expected: true},
+		{expression: "transport == '&&'", expected: false},
+		{expression: "replicas > 1", expected: true},
+		{expression: "replicas <= 2", expected: false},
+		{expression: "replicas == 3 && replicas >= 3 && replicas < 4", expected: true},
+		{expression: "'auth' in features", expected: true},
+		{expression: "'tracing' in features", expected: false},
+		{expression: "features && !debug", expected: true},
+		{expression: "debug == false", expected: true},
+	}
+
+	for _, test := range tests {
+		actual, err := evalCondition(test.expression, values)
+		if err != nil {
+			t.Errorf("Failed to evaluate %q: %v", test.expression, err)
+			continue
+		}
+
+		if actual != test.expected {
+			t.Errorf("Expected %q to be %t", test.expression, test.expected)
+		}
+	}
+
+	for _, expression := range []string{"", "transport ==", "(transport", "transport 'grpc'", "transport = 'grpc'", "'unterminated"} {
+		if _, err := parseCondition(expression); err == nil {
+			t.Errorf("Expected a syntax error for %q", expression)
+		}
+	}
+
+	for _, expression := range []string{"missing", "transport > 1", "'auth' in replicas"} {
+		if _, err := evalCondition(expression, values); err == nil {
+			t.Errorf("Expected an error evaluating %q", expression)
+		}
+	}
+}
+
+func TestMakeConditionalInclude(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "transport"
+			choices = ["grpc", "http"]
+
+			[[token]]
+			name = "worker"
+			default = "false"
+
+			[[include]]
+			path = "grpc"
+			when = "transport == 'grpc'"
+
+			[[include]]
+			path = "http"
+			when = "transport == 'http'"
+
+			[[include]]
+			path = "cmd/worker.go"
+			when = "worker"
+		`)},
+		"grpc/server.go": &fstest.MapFile{Data: []byte("package grpc")},
+		"http/server.go": &fstest.MapFile{Data: []byte("package http")},
+		"cmd/worker.go":  &fstest.MapFile{Data: []byte("package main")},
+		"cmd/main.go":    &fstest.MapFile{Data: []byte("package main")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("transport", "grpc")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	if len(out.Files) != 2 || out.Files["grpc/server.go"] == nil || out.Files["cmd/main.go"] == nil {
+		t.Errorf("Unexpected generated files: %v", out.Files)
+	}
+
+	if _, ok := out.Dirs["http"]; ok {
+		t.Errorf("Excluded http directory was created")
+	}
+}
+
+func TestInvalidIncludeCondition(t *testing.T) {
+	_, err := parseConfig([]byte(`
+		[[include]]
+		path = "grpc"
+		when = "transport =="
+	`))
+	if err == nil {
+		t.Errorf("Expected an error for an invalid condition")
+	}
+}
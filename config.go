@@ -1,35 +1,213 @@
 package scaffold
 
 import (
+	"fmt"
 	"github.com/pelletier/go-toml/v2"
-	"os"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
 )
 
 type Token struct {
-	Name      string   `toml:"name"`
-	Value     string   `toml:"value"`
-	Modifiers []string `toml:"modifiers"`
-	Localize  []string `toml:"localize"`
-	Priority  int      `toml:"priority"`
-	Token     string   `toml:"token"`
+	Name         string   `toml:"name"`
+	Value        string   `toml:"value"`
+	Modifiers    []string `toml:"modifiers"`
+	Localize     []string `toml:"localize"`
+	Priority     int      `toml:"priority"`
+	Token        string   `toml:"token"`
+	Required     *bool    `toml:"required"`
+	Default      string   `toml:"default"`
+	Pattern      string   `toml:"pattern"`
+	MinLength    int      `toml:"min_length"`
+	MaxLength    int      `toml:"max_length"`
+	Choices      []string `toml:"choices"`
+	Type         string   `toml:"type"`
+	Separator    string   `toml:"separator"`
+	Match        string   `toml:"match"`
+	Variants     bool     `toml:"variants"`
+	PreserveCase bool     `toml:"preserve_case"`
+
+	// Human facing metadata for front-ends collecting values
+	Description string `toml:"description"`
+	Prompt      string `toml:"prompt"`
+	Example     string `toml:"example"`
+	Group       string `toml:"group"`
+
+	// input is the resolved value before modifiers were applied, and typed
+	// the resolved value parsed according to Type
+	input string
+	typed any
+}
+
+// IsRequired reports whether the token needs a non-empty value. Unless set
+// explicitly, tokens without a binding or static value are required.
+func (token Token) IsRequired() bool {
+	if token.Required != nil {
+		return *token.Required
+	}
+
+	return token.IsInput()
+}
+
+// IsInput reports whether the token's value is supplied by the user rather
+// than bound to another token or set in the config.
+func (token Token) IsInput() bool {
+	return token.Token == "" && token.Value == ""
+}
+
+// PathRule overrides behaviour for the template paths matched by Path, which
+// may be an exact path, a directory prefix or a glob.
+type PathRule struct {
+	Path     string         `toml:"path"`
+	Conflict ConflictPolicy `toml:"conflict"`
+	Render   string         `toml:"render"`
+	Mode     string         `toml:"mode"`
+}
+
+// IncludeRule only generates the template paths matched by Path when the
+// When condition holds for the token values.
+type IncludeRule struct {
+	Path string `toml:"path"`
+	When string `toml:"when"`
+}
+
+// RepeatRule generates the template file or directory at Path once for every
+// item of the list token Over, with the token Item set to the item.
+type RepeatRule struct {
+	Path string `toml:"path"`
+	Over string `toml:"over"`
+	Item string `toml:"item"`
+}
+
+const (
+	defaultEscape = `\`
+)
+
+// Delimiters make tokens only match between Open and Close, as in __name__
+// or {{name}}. A token preceded by Escape, a backslash by default, is left in
+// place without it.
+type Delimiters struct {
+	Open   string `toml:"open"`
+	Close  string `toml:"close"`
+	Escape string `toml:"escape"`
+}
+
+func (delimiters Delimiters) escape() string {
+	if delimiters.Escape == "" {
+		return defaultEscape
+	}
+
+	return delimiters.Escape
 }
 
 type Config struct {
-	Tokens []Token `toml:"token"`
+	Version    string        `toml:"version"`
+	Delimiters Delimiters    `toml:"delimiters"`
+	Tokens     []Token       `toml:"token"`
+	Ignore     []string      `toml:"ignore"`
+	Includes   []IncludeRule `toml:"include"`
+	Repeats    []RepeatRule  `toml:"repeat"`
+	Injects    []InjectRule  `toml:"inject"`
+	Paths      []PathRule    `toml:"path"`
+}
+
+func getConfigFS(fsys fs.FS, configPath string) (Config, error) {
+	content, err := fs.ReadFile(fsys, configPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return parseConfig(content)
 }
 
-func getConfig(configPath string) (Config, error) {
+func parseConfig(content []byte) (Config, error) {
 	config := Config{}
 
-	content, err := os.ReadFile(configPath)
+	err := toml.Unmarshal(content, &config)
 	if err != nil {
 		return config, err
 	}
 
-	err = toml.Unmarshal(content, &config)
-	if err != nil {
-		return config, err
+	if config.Delimiters.Open == "" && (config.Delimiters.Close != "" || config.Delimiters.Escape != "") {
+		return config, fmt.Errorf("delimiters: open is required")
+	}
+
+	for _, token := range config.Tokens {
+		if !validType(token.Type) {
+			return config, fmt.Errorf("token %q: unknown type %q", token.Name, token.Type)
+		}
+
+		if !validMatch(token.Match) {
+			return config, fmt.Errorf("token %q: unknown match mode %q", token.Name, token.Match)
+		}
+
+		if token.Pattern == "" {
+			continue
+		}
+
+		if _, err := regexp.Compile(token.Pattern); err != nil {
+			return config, fmt.Errorf("token %q: invalid pattern: %w", token.Name, err)
+		}
+	}
+
+	for _, rule := range config.Includes {
+		if _, err := parseCondition(rule.When); err != nil {
+			return config, fmt.Errorf("include %q: %w", rule.Path, err)
+		}
+	}
+
+	for _, rule := range config.Repeats {
+		if rule.Over == "" || rule.Item == "" {
+			return config, fmt.Errorf("repeat %q: over and item are required", rule.Path)
+		}
+
+		// Items that are not declared as tokens are bound to the list, so
+		// they are never asked for
+		if _, ok := findToken(config.Tokens, rule.Item); !ok {
+			config.Tokens = append(config.Tokens, Token{Name: rule.Item, Token: rule.Over})
+		}
+	}
+
+	for _, rule := range config.Injects {
+		if err := rule.validate(); err != nil {
+			return config, fmt.Errorf("inject %q: %w", rule.File, err)
+		}
+	}
+
+	for _, rule := range config.Paths {
+		if rule.Conflict != "" && !rule.Conflict.valid() {
+			return config, fmt.Errorf("path %q: unknown conflict policy %q", rule.Path, rule.Conflict)
+		}
+
+		if !validRender(rule.Render) {
+			return config, fmt.Errorf("path %q: unknown render mode %q", rule.Path, rule.Render)
+		}
+
+		if rule.Mode != "" {
+			if _, err := parsePerm(rule.Mode); err != nil {
+				return config, fmt.Errorf("path %q: %w", rule.Path, err)
+			}
+		}
 	}
 
 	return config, nil
 }
+
+func (rule PathRule) matches(relativePath string) bool {
+	return matchPath(rule.Path, relativePath)
+}
+
+// matchPath reports whether a slash separated template path is the pattern
+// itself, lives below it, or matches it as a glob.
+func matchPath(pattern string, relativePath string) bool {
+	pattern = strings.Trim(pattern, "/")
+
+	if relativePath == pattern || strings.HasPrefix(relativePath, pattern+"/") {
+		return true
+	}
+
+	matched, err := path.Match(pattern, relativePath)
+
+	return err == nil && matched
+}
@@ -0,0 +1,94 @@
+package scaffold
+
+import (
+	"fmt"
+)
+
+// ConflictPolicy decides what Make does when a file it would write already
+// exists in the destination.
+type ConflictPolicy string
+
+const (
+	ConflictFail      ConflictPolicy = "fail"
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictNew       ConflictPolicy = "new"
+	ConflictPrompt    ConflictPolicy = "prompt"
+)
+
+const conflictNewSuffix = ".new"
+
+type ConflictError struct {
+	Path string
+}
+
+func (err *ConflictError) Error() string {
+	return fmt.Sprintf("destination file already exists: %s", err.Path)
+}
+
+func (policy ConflictPolicy) valid() bool {
+	switch policy {
+	case ConflictFail, ConflictSkip, ConflictOverwrite, ConflictNew, ConflictPrompt:
+		return true
+	}
+
+	return false
+}
+
+func (scaf *Scaffold) SetConflictPolicy(policy ConflictPolicy) error {
+	if !policy.valid() {
+		return fmt.Errorf("unknown conflict policy %q", policy)
+	}
+
+	scaf.conflictPolicy = policy
+
+	return nil
+}
+
+// OnConflict registers the callback used by ConflictPrompt. It receives the
+// existing destination path and returns the policy to apply to it.
+func (scaf *Scaffold) OnConflict(onConflictFunc func(string) ConflictPolicy) {
+	scaf.onConflictFunc = onConflictFunc
+}
+
+// conflictPolicyFor returns the policy for a template path, preferring the
+// last matching path rule in scaffold.toml over the Scaffold-wide policy.
+func (scaf *Scaffold) conflictPolicyFor(relativePath string) ConflictPolicy {
+	policy := scaf.conflictPolicy
+
+	for _, rule := range scaf.Config.Paths {
+		if rule.Conflict != "" && rule.matches(relativePath) {
+			policy = rule.Conflict
+		}
+	}
+
+	return policy
+}
+
+// resolveConflict turns the policy for an existing destination file into a
+// concrete one, asking the OnConflict callback when the policy is prompt.
+// Without ask the prompt policy is returned as is, so dry runs never prompt.
+func (scaf *Scaffold) resolveConflict(relativePath string, destination string, ask bool) (ConflictPolicy, error) {
+	policy := scaf.conflictPolicyFor(relativePath)
+
+	if policy == ConflictPrompt {
+		if scaf.onConflictFunc == nil {
+			return "", fmt.Errorf("conflict policy is prompt but no OnConflict callback is registered")
+		}
+
+		if !ask {
+			return policy, nil
+		}
+
+		policy = scaf.onConflictFunc(destination)
+		if policy == ConflictPrompt || !policy.valid() {
+			return "", fmt.Errorf("invalid conflict policy %q returned for %s", policy, destination)
+		}
+	}
+
+	if policy == ConflictFail {
+		return "", &ConflictError{Path: destination}
+	}
+
+	return policy, nil
+}
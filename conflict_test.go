@@ -0,0 +1,162 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakeConflictPolicies(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+		value = "generated"
+
+		[[path]]
+		path = "keep"
+		conflict = "skip"
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"main.txt":     "{{name}}",
+		"keep/own.txt": "{{name}}",
+	})
+
+	tests := []struct {
+		policy   ConflictPolicy
+		main     string
+		mainNew  string
+		wantErr  bool
+		prompted ConflictPolicy
+	}{
+		{policy: ConflictOverwrite, main: "generated"},
+		{policy: ConflictSkip, main: "edited"},
+		{policy: ConflictNew, main: "edited", mainNew: "generated"},
+		{policy: ConflictFail, main: "edited", wantErr: true},
+		{policy: ConflictPrompt, main: "edited", mainNew: "generated", prompted: ConflictNew},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			destDir := t.TempDir()
+
+			err := os.MkdirAll(filepath.Join(destDir, "keep"), 0755)
+			if err != nil {
+				t.Fatalf("Failed to create output dir: %v", err)
+			}
+
+			for _, name := range []string{"main.txt", "keep/own.txt"} {
+				err = os.WriteFile(filepath.Join(destDir, name), []byte("edited"), 0644)
+				if err != nil {
+					t.Fatalf("Failed to write existing file: %v", err)
+				}
+			}
+
+			scaf, err := Init(templateDir)
+			if err != nil {
+				t.Fatalf("Failed to init scaffold: %v", err)
+			}
+
+			err = scaf.SetConflictPolicy(test.policy)
+			if err != nil {
+				t.Fatalf("Failed to set conflict policy: %v", err)
+			}
+
+			var prompts []string
+			scaf.OnConflict(func(path string) ConflictPolicy {
+				prompts = append(prompts, path)
+				return test.prompted
+			})
+
+			err = scaf.Make(destDir)
+
+			var conflictErr *ConflictError
+			if test.wantErr != errors.As(err, &conflictErr) {
+				t.Fatalf("Unexpected error from Make: %v", err)
+			}
+
+			main, _ := os.ReadFile(filepath.Join(destDir, "main.txt"))
+			if string(main) != test.main {
+				t.Errorf("Expected main.txt to contain %q, got %q", test.main, string(main))
+			}
+
+			mainNew, _ := os.ReadFile(filepath.Join(destDir, "main.txt.new"))
+			if string(mainNew) != test.mainNew {
+				t.Errorf("Expected main.txt.new to contain %q, got %q", test.mainNew, string(mainNew))
+			}
+
+			// The per-path rule always wins over the Scaffold-wide policy
+			own, _ := os.ReadFile(filepath.Join(destDir, "keep", "own.txt"))
+			if string(own) != "edited" {
+				t.Errorf("Expected keep/own.txt to be skipped, got %q", string(own))
+			}
+
+			if test.policy == ConflictPrompt && len(prompts) != 1 {
+				t.Errorf("Expected a single prompt, got %v", prompts)
+			}
+		})
+	}
+}
+
+func TestSetConflictPolicyRejectsUnknown(t *testing.T) {
+	scaf := &Scaffold{}
+
+	if err := scaf.SetConflictPolicy("clobber"); err == nil {
+		t.Errorf("Expected an error for an unknown conflict policy")
+	}
+}
+
+func TestPlanDoesNotPrompt(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+		value = "generated"
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"main.txt": "{{name}}",
+	})
+
+	destDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(destDir, "main.txt"), []byte("edited"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write existing file: %v", err)
+	}
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	err = scaf.SetConflictPolicy(ConflictPrompt)
+	if err != nil {
+		t.Fatalf("Failed to set conflict policy: %v", err)
+	}
+
+	prompts := 0
+	scaf.OnConflict(func(path string) ConflictPolicy {
+		prompts++
+		return ConflictOverwrite
+	})
+
+	operations, err := scaf.Plan(destDir)
+	if err != nil {
+		t.Fatalf("Failed to plan: %v", err)
+	}
+
+	if prompts != 0 {
+		t.Errorf("Expected Plan not to prompt, got %d prompts", prompts)
+	}
+
+	if len(operations) == 0 || operations[0].Type != OpPrompt {
+		t.Fatalf("Expected main.txt to be planned as a prompt, got %+v", operations)
+	}
+
+	if err := scaf.Make(destDir); err != nil {
+		t.Fatalf("Failed to make: %v", err)
+	}
+
+	if prompts != 1 {
+		t.Errorf("Expected Make to prompt once, got %d prompts", prompts)
+	}
+}
@@ -5,4 +5,5 @@ go 1.23.0
 require (
 	github.com/pelletier/go-toml/v2 v2.2.4
 	github.com/gertd/go-pluralize v0.2.1
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,83 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const (
+	RenderReplace    = "replace"
+	RenderGoTemplate = "gotemplate"
+)
+
+const (
+	goTemplateSuffix = ".tmpl"
+)
+
+func validRender(render string) bool {
+	switch render {
+	case "", RenderReplace, RenderGoTemplate:
+		return true
+	}
+
+	return false
+}
+
+// renderMode returns how the content of a template file is rendered. Files
+// ending in .tmpl are Go templates, and the last matching path rule with a
+// render mode overrides that.
+func (scaf *Scaffold) renderMode(relativePath string) string {
+	mode := RenderReplace
+	if strings.HasSuffix(relativePath, goTemplateSuffix) {
+		mode = RenderGoTemplate
+	}
+
+	for _, rule := range scaf.Config.Paths {
+		if rule.Render != "" && rule.matches(relativePath) {
+			mode = rule.Render
+		}
+	}
+
+	return mode
+}
+
+// executeTemplate renders content with text/template. The resolved token
+// values are the template's data, and every registered modifier is available
+// as a function, so `{{ .name | pascal }}` or `{{ range .entities }}` work.
+func (scaf *Scaffold) executeTemplate(name string, content string, values map[string]any) (string, error) {
+	funcs := make(template.FuncMap, len(scaf.Modifiers))
+	for modifier := range scaf.Modifiers {
+		if !isIdentifier(modifier) {
+			continue
+		}
+
+		funcs[modifier] = func(value any) string {
+			return scaf.applyModifiers([]string{modifier}, fmt.Sprint(value))
+		}
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, values); err != nil {
+		return "", err
+	}
+
+	return rendered.String(), nil
+}
+
+// isIdentifier reports whether name can be used as a template function name.
+func isIdentifier(name string) bool {
+	for i, char := range name {
+		if char != '_' && !unicode.IsLetter(char) && (i == 0 || !unicode.IsDigit(char)) {
+			return false
+		}
+	}
+
+	return name != ""
+}
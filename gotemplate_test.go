@@ -0,0 +1,114 @@
+package scaffold
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestGoTemplateRendering(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "service"
+
+			[[token]]
+			name = "entities"
+			type = "list"
+
+			[[token]]
+			name = "metrics"
+			type = "bool"
+
+			[[path]]
+			path = "config/*.yaml"
+			render = "gotemplate"
+
+			[[path]]
+			path = "raw/literal.tmpl"
+			render = "replace"
+		`)},
+		"service/main.go.tmpl": &fstest.MapFile{Data: []byte(
+			"package {{ .service | lower }}\n" +
+				"{{ range .entities }}type {{ . | pascal }} struct{}\n{{ end }}" +
+				"{{ if .metrics }}// metrics enabled\n{{ end }}",
+		)},
+		"config/app.yaml":   &fstest.MapFile{Data: []byte("name: {{ .service | slug }}\n")},
+		"raw/literal.tmpl":  &fstest.MapFile{Data: []byte("{{ service }}")},
+		"plain/service.txt": &fstest.MapFile{Data: []byte("service {{ .service }}")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("service", "BillingService")
+	scaf.RegisterTokenValue("entities", "invoice, line_item")
+	scaf.RegisterTokenValue("metrics", "true")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected := map[string]string{
+		"BillingService/main.go":   "package billingservice\ntype Invoice struct{}\ntype LineItem struct{}\n// metrics enabled\n",
+		"config/app.yaml":          "name: billing-service\n",
+		"raw/literal.tmpl":         "{{ BillingService }}",
+		"plain/BillingService.txt": "BillingService {{ .BillingService }}",
+	}
+
+	if len(out.Files) != len(expected) {
+		t.Errorf("Unexpected files: %v", out.Files)
+	}
+
+	for name, content := range expected {
+		file, ok := out.Files[name]
+		if !ok {
+			t.Errorf("Expected %s to be generated", name)
+			continue
+		}
+
+		if string(file.Data) != content {
+			t.Errorf("Unexpected content in %s.\nExpected:\n%s\nGot:\n%s", name, content, file.Data)
+		}
+	}
+}
+
+func TestGoTemplateErrors(t *testing.T) {
+	for _, content := range []string{"{{ .missing }}", "{{ if .service }}", "{{ .service | unknown }}"} {
+		templates := fstest.MapFS{
+			"scaffold.toml": &fstest.MapFile{Data: []byte(`
+				[[token]]
+				name = "service"
+			`)},
+			"file.tmpl": &fstest.MapFile{Data: []byte(content)},
+		}
+
+		scaf, err := InitFS(templates, ".")
+		if err != nil {
+			t.Fatalf("Failed to init scaffold: %v", err)
+		}
+
+		scaf.RegisterTokenValue("service", "billing")
+
+		out := NewMemoryOutput()
+		if err := scaf.MakeTo(out); err == nil {
+			t.Errorf("Expected an error rendering %q", content)
+		}
+
+		if len(out.Files) != 0 {
+			t.Errorf("Expected nothing to be written for %q, got %v", content, out.Files)
+		}
+	}
+
+	if _, err := parseConfig([]byte(`
+		[[path]]
+		path = "*.txt"
+		render = "jinja"
+	`)); err == nil {
+		t.Errorf("Expected an error for an unknown render mode")
+	}
+}
@@ -0,0 +1,136 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const (
+	ignoreFileName = ".scaffoldignore"
+)
+
+// ignoreRule is a single gitignore style pattern.
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreRules holds patterns in the order they were declared, where the last
+// matching pattern decides whether a path is ignored.
+type ignoreRules []ignoreRule
+
+func parseIgnoreRules(patterns []string) ignoreRules {
+	var rules ignoreRules
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimRight(pattern, " \t\r")
+
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+			pattern = pattern[1:]
+		}
+
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimRight(pattern, "/")
+		}
+
+		// Patterns with a slash anywhere but the end match from the root
+		if strings.Contains(pattern, "/") {
+			rule.anchored = true
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+
+		if pattern == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(pattern, "/")
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// ignored reports whether a slash separated template path is excluded.
+func (rules ignoreRules) ignored(name string, isDir bool) bool {
+	ignored := false
+	segments := strings.Split(name, "/")
+
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if rule.matches(segments) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+func (rule ignoreRule) matches(segments []string) bool {
+	if !rule.anchored {
+		return matchSegment(rule.segments[0], segments[len(segments)-1])
+	}
+
+	return matchSegments(rule.segments, segments)
+}
+
+// matchSegments matches path segments against pattern segments, where a "**"
+// segment matches any number of path segments.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 || !matchSegment(pattern[0], segments[0]) {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+func matchSegment(pattern string, segment string) bool {
+	matched, err := path.Match(pattern, segment)
+
+	return err == nil && matched
+}
+
+// ignoreRules combines the ignore list from scaffold.toml with the template's
+// .scaffoldignore file, whose patterns take precedence.
+func (scaf *Scaffold) ignoreRules() (ignoreRules, error) {
+	patterns := append([]string{}, scaf.Config.Ignore...)
+
+	content, err := fs.ReadFile(scaf.FS, ignoreFileName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	patterns = append(patterns, strings.Split(string(content), "\n")...)
+
+	return parseIgnoreRules(patterns), nil
+}
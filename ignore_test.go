@@ -0,0 +1,86 @@
+package scaffold
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules := parseIgnoreRules([]string{
+		"# editor files",
+		"*.swp",
+		".git/",
+		"/TEMPLATE.md",
+		"docs/**/*.draft",
+		"fixtures/",
+		"build",
+		"!build/keep.txt",
+		`\#literal`,
+	})
+
+	tests := []struct {
+		name    string
+		isDir   bool
+		ignored bool
+	}{
+		{name: "main.go.swp", ignored: true},
+		{name: "cmd/main.go.swp", ignored: true},
+		{name: "main.go"},
+		{name: ".git", isDir: true, ignored: true},
+		{name: "sub/.git", isDir: true, ignored: true},
+		{name: ".git"},
+		{name: "TEMPLATE.md", ignored: true},
+		{name: "docs/TEMPLATE.md"},
+		{name: "docs/intro.draft", ignored: true},
+		{name: "docs/a/b/intro.draft", ignored: true},
+		{name: "docs/intro.md"},
+		{name: "fixtures", isDir: true, ignored: true},
+		{name: "build", isDir: true, ignored: true},
+		{name: "build/keep.txt"},
+		{name: "#literal", ignored: true},
+	}
+
+	for _, test := range tests {
+		if ignored := rules.ignored(test.name, test.isDir); ignored != test.ignored {
+			t.Errorf("Expected ignored(%q, dir=%t) to be %t", test.name, test.isDir, test.ignored)
+		}
+	}
+}
+
+func TestMakeHonoursIgnore(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			ignore = ["TEMPLATE.md", "testdata/"]
+		`)},
+		".scaffoldignore":    &fstest.MapFile{Data: []byte(".git/\n*.swp\n")},
+		"TEMPLATE.md":        &fstest.MapFile{Data: []byte("notes for template authors")},
+		"main.go":            &fstest.MapFile{Data: []byte("package main")},
+		".main.go.swp":       &fstest.MapFile{Data: []byte("swap")},
+		".git/HEAD":          &fstest.MapFile{Data: []byte("ref: refs/heads/main")},
+		"testdata/input.txt": &fstest.MapFile{Data: []byte("fixture")},
+		"cmd/app/main.go":    &fstest.MapFile{Data: []byte("package main")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	if len(out.Files) != 2 || out.Files["main.go"] == nil || out.Files["cmd/app/main.go"] == nil {
+		t.Errorf("Unexpected generated files: %v", out.Files)
+	}
+
+	for _, dir := range []string{".git", "testdata"} {
+		if _, ok := out.Dirs[dir]; ok {
+			t.Errorf("Ignored directory %s was created", dir)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	InjectBefore = "before"
+	InjectAfter  = "after"
+)
+
+// InjectRule inserts Content into File, an existing destination file, on the
+// line before or after the first line containing Marker or matching Pattern,
+// in which ^ and $ match at line boundaries. Content that is already present
+// is not inserted again.
+type InjectRule struct {
+	File     string `toml:"file"`
+	Marker   string `toml:"marker"`
+	Pattern  string `toml:"pattern"`
+	Position string `toml:"position"`
+	Content  string `toml:"content"`
+}
+
+func (rule InjectRule) validate() error {
+	if rule.File == "" {
+		return errors.New("file is required")
+	}
+
+	if (rule.Marker == "") == (rule.Pattern == "") {
+		return errors.New("exactly one of marker and pattern is required")
+	}
+
+	if rule.Pattern != "" {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+
+	switch rule.Position {
+	case "", InjectBefore, InjectAfter:
+		return nil
+	}
+
+	return fmt.Errorf("unknown position %q", rule.Position)
+}
+
+// inject inserts content into subject relative to the line the rule matches,
+// reporting false when the content is already there.
+func (rule InjectRule) inject(subject string, content string) (string, bool, error) {
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	if strings.Contains(subject, content) {
+		return subject, false, nil
+	}
+
+	start := -1
+	if rule.Marker != "" {
+		start = strings.Index(subject, rule.Marker)
+	} else if match := regexp.MustCompile("(?m)" + rule.Pattern).FindStringIndex(subject); match != nil {
+		start = match[0]
+	}
+
+	if start < 0 {
+		return "", false, fmt.Errorf("no line matches %q", rule.Marker+rule.Pattern)
+	}
+
+	// Insert whole lines, at the start of the matching line or after its end
+	offset := strings.LastIndexByte(subject[:start], '\n') + 1
+
+	if rule.Position != InjectBefore {
+		if end := strings.IndexByte(subject[start:], '\n'); end >= 0 {
+			offset = start + end + 1
+		} else {
+			offset = len(subject)
+			content = "\n" + content
+		}
+	}
+
+	return subject[:offset] + content + subject[offset:], true, nil
+}
+
+// planInjects adds an inject operation for every rule, applied to the
+// contents planned for the file or else to the file already in the output.
+func (planner *planner) planInjects(replacer *replacer) error {
+	scaf := planner.scaf
+
+	for _, rule := range scaf.Config.Injects {
+		name := replacer.replace(rule.File, rule.File)
+		content := replacer.replace(rule.Content, rule.File)
+
+		planned := -1
+		for i, operation := range planner.operations {
+			if operation.name == name && operation.Type != OpMkdir && operation.Type != OpSkip {
+				planned = i
+			}
+		}
+
+		if planned < 0 && !planner.injectExisting {
+			continue
+		}
+
+		var current string
+		if planned >= 0 {
+			current = string(planner.operations[planned].contents)
+		} else {
+			editable, ok := planner.out.(EditableOutput)
+			if !ok {
+				return fmt.Errorf("inject %s: output does not support reading files", name)
+			}
+
+			contents, err := editable.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("inject %s: %w", name, err)
+			}
+
+			current = string(contents)
+		}
+
+		injected, changed, err := rule.inject(current, content)
+		if err != nil {
+			return fmt.Errorf("inject %s: %w", name, err)
+		}
+
+		if planned >= 0 {
+			planner.operations[planned].contents = []byte(injected)
+			planner.operations[planned].Size = len(injected)
+
+			continue
+		}
+
+		operation := Operation{
+			Type:     OpInject,
+			Path:     outputPath(planner.root, name),
+			Size:     len(injected),
+			name:     name,
+			contents: []byte(injected),
+			perm:     defaultFilePerm,
+		}
+
+		if !changed {
+			operation.Type = OpSkip
+		}
+
+		planner.operations = append(planner.operations, operation)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInjectRule(t *testing.T) {
+	subject := "func routes() {\n\t// scaffold:routes\n}\n"
+
+	tests := []struct {
+		rule     InjectRule
+		content  string
+		expected string
+	}{
+		{
+			rule:     InjectRule{Marker: "// scaffold:routes"},
+			content:  "\tregister(users)",
+			expected: "func routes() {\n\t// scaffold:routes\n\tregister(users)\n}\n",
+		},
+		{
+			rule:     InjectRule{Marker: "scaffold:routes", Position: InjectBefore},
+			content:  "\tregister(users)\n",
+			expected: "func routes() {\n\tregister(users)\n\t// scaffold:routes\n}\n",
+		},
+		{
+			rule:     InjectRule{Pattern: `^}`, Position: InjectAfter},
+			content:  "// end",
+			expected: "func routes() {\n\t// scaffold:routes\n}\n// end\n",
+		},
+		{
+			rule:     InjectRule{Marker: "func routes() {"},
+			content:  "\t// scaffold:routes",
+			expected: subject,
+		},
+	}
+
+	for _, test := range tests {
+		injected, _, err := test.rule.inject(subject, test.content)
+		if err != nil {
+			t.Errorf("Failed to inject %q: %v", test.content, err)
+			continue
+		}
+
+		if injected != test.expected {
+			t.Errorf("Unexpected result injecting %q.\nExpected:\n%s\nGot:\n%s", test.content, test.expected, injected)
+		}
+	}
+
+	// Markers on the last line without a newline still get whole lines
+	injected, _, _ := InjectRule{Marker: "last"}.inject("last", "next")
+	if injected != "last\nnext\n" {
+		t.Errorf("Unexpected result injecting after the last line: %q", injected)
+	}
+
+	if _, _, err := (InjectRule{Marker: "missing"}).inject(subject, "x"); err == nil {
+		t.Errorf("Expected an error for a missing marker")
+	}
+}
+
+func TestInject(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "entity"
+
+			[[token]]
+			name = "Entity"
+			token = "entity"
+			modifiers = ["pascal"]
+
+			[[inject]]
+			file = "router.go"
+			marker = "// scaffold:routes"
+			content = "\tr.Handle(\"/entity\", handlers.Entity)"
+
+			[[inject]]
+			file = "handlers/entity.go"
+			pattern = "^package"
+			content = "\n// Entity handlers"
+		`)},
+		"handlers/entity.go": &fstest.MapFile{Data: []byte("package handlers\n")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("entity", "invoice")
+
+	var made []string
+	scaf.OnMake(func(path string) {
+		made = append(made, path)
+	})
+
+	out := NewMemoryOutput()
+
+	// Injecting into a file that does not exist fails before anything is written
+	if err := scaf.MakeTo(out); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected a missing file error, got %v", err)
+	}
+
+	if len(out.Files) != 0 {
+		t.Fatalf("Expected nothing to be written, got %v", out.Files)
+	}
+
+	router := "func routes(r *Router) {\n\t// scaffold:routes\n}\n"
+	out.WriteFile("router.go", []byte(router), 0644)
+
+	for range 2 {
+		if err := scaf.MakeTo(out); err != nil {
+			t.Fatalf("Failed to make scaffold: %v", err)
+		}
+	}
+
+	expected := "func routes(r *Router) {\n\t// scaffold:routes\n\tr.Handle(\"/invoice\", handlers.Invoice)\n}\n"
+	if string(out.Files["router.go"].Data) != expected {
+		t.Errorf("Unexpected router.\nExpected:\n%s\nGot:\n%s", expected, out.Files["router.go"].Data)
+	}
+
+	expected = "package handlers\n\n// Invoice handlers\n"
+	if string(out.Files["handlers/invoice.go"].Data) != expected {
+		t.Errorf("Unexpected handler.\nExpected:\n%s\nGot:\n%s", expected, out.Files["handlers/invoice.go"].Data)
+	}
+
+	// The second run leaves the router alone
+	if len(made) != 3 || made[0] != "handlers/invoice.go" || made[1] != "router.go" || made[2] != "handlers/invoice.go" {
+		t.Errorf("Unexpected OnMake events: %v", made)
+	}
+
+	operations, err := scaf.PlanTo(out)
+	if err != nil {
+		t.Fatalf("Failed to plan scaffold: %v", err)
+	}
+
+	if last := operations[len(operations)-1]; last.Type != OpSkip || last.Path != "router.go" {
+		t.Errorf("Expected the router injection to be skipped, got %+v", last)
+	}
+}
+
+func TestInjectConfig(t *testing.T) {
+	for _, config := range []string{
+		`[[inject]]
+		marker = "x"`,
+		`[[inject]]
+		file = "router.go"`,
+		`[[inject]]
+		file = "router.go"
+		marker = "x"
+		pattern = "x"`,
+		`[[inject]]
+		file = "router.go"
+		pattern = "("`,
+		`[[inject]]
+		file = "router.go"
+		marker = "x"
+		position = "inside"`,
+	} {
+		if _, err := parseConfig([]byte(config)); err == nil {
+			t.Errorf("Expected an error for config:\n%s", config)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package scaffold
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	conflictStart  = "<<<<<<< current\n"
+	conflictMiddle = "=======\n"
+	conflictEnd    = ">>>>>>> template\n"
+)
+
+// merge3 merges the changes from base to ours and from base to theirs line
+// by line. Changes that overlap and differ are kept side by side between
+// conflict markers, in which case conflicted is true.
+func merge3(base string, ours string, theirs string) (merged string, conflicted bool) {
+	baseLines := splitLines(base)
+	ourLines := splitLines(ours)
+	theirLines := splitLines(theirs)
+
+	ourMatches := matchLines(baseLines, ourLines)
+	theirMatches := matchLines(baseLines, theirLines)
+
+	var result strings.Builder
+
+	// resolve writes the unstable chunk between two lines all sides agree on
+	resolve := func(baseChunk []string, ourChunk []string, theirChunk []string) {
+		switch {
+		case slices.Equal(ourChunk, baseChunk):
+			writeLines(&result, theirChunk)
+		case slices.Equal(theirChunk, baseChunk), slices.Equal(ourChunk, theirChunk):
+			writeLines(&result, ourChunk)
+		default:
+			conflicted = true
+
+			result.WriteString(conflictStart)
+			writeLines(&result, terminateLines(ourChunk))
+			result.WriteString(conflictMiddle)
+			writeLines(&result, terminateLines(theirChunk))
+			result.WriteString(conflictEnd)
+		}
+	}
+
+	i, a, b := 0, 0, 0
+
+	for k := range baseLines {
+		ourIndex, theirIndex := ourMatches[k], theirMatches[k]
+		if ourIndex < 0 || theirIndex < 0 {
+			continue
+		}
+
+		resolve(baseLines[i:k], ourLines[a:ourIndex], theirLines[b:theirIndex])
+		result.WriteString(baseLines[k])
+
+		i, a, b = k+1, ourIndex+1, theirIndex+1
+	}
+
+	resolve(baseLines[i:], ourLines[a:], theirLines[b:])
+
+	return result.String(), conflicted
+}
+
+// splitLines splits text after every newline, keeping the line endings.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+func writeLines(builder *strings.Builder, lines []string) {
+	for _, line := range lines {
+		builder.WriteString(line)
+	}
+}
+
+// terminateLines makes sure the last line ends with a newline, so conflict
+// markers always start on a line of their own.
+func terminateLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+
+	lines = slices.Clone(lines)
+	lines[len(lines)-1] += "\n"
+
+	return lines
+}
+
+// matchLines returns, for every line in a, the index of the line it matches
+// in b as part of a shortest edit script, or -1 when it was removed. It uses
+// the linear space variant of the algorithm by Eugene Myers, so memory stays
+// proportional to the number of lines however much they differ.
+func matchLines(a []string, b []string) []int {
+	matches := make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	size := len(a) + len(b) + 3
+	matcher := &lineMatcher{
+		a:        a,
+		b:        b,
+		matches:  matches,
+		forward:  make([]int, size),
+		backward: make([]int, size),
+	}
+
+	matcher.compare(0, len(a), 0, len(b))
+
+	return matches
+}
+
+// lineMatcher holds the state shared by the recursive comparisons of
+// matchLines, including the furthest points reached on every diagonal.
+type lineMatcher struct {
+	a        []string
+	b        []string
+	matches  []int
+	forward  []int
+	backward []int
+}
+
+// compare matches the lines of a[aLow:aHigh] to those of b[bLow:bHigh] by
+// splitting both at the middle snake of a shortest edit script.
+func (matcher *lineMatcher) compare(aLow int, aHigh int, bLow int, bHigh int) {
+	a, b := matcher.a, matcher.b
+
+	for aLow < aHigh && bLow < bHigh && a[aLow] == b[bLow] {
+		matcher.matches[aLow] = bLow
+		aLow++
+		bLow++
+	}
+
+	for aLow < aHigh && bLow < bHigh && a[aHigh-1] == b[bHigh-1] {
+		aHigh--
+		bHigh--
+		matcher.matches[aHigh] = bHigh
+	}
+
+	if aLow == aHigh || bLow == bHigh {
+		return
+	}
+
+	x, y, u, v := matcher.middleSnake(aLow, aHigh, bLow, bHigh)
+
+	matcher.compare(aLow, x, bLow, y)
+
+	for ; x < u; x, y = x+1, y+1 {
+		matcher.matches[x] = y
+	}
+
+	matcher.compare(u, aHigh, v, bHigh)
+}
+
+// middleSnake searches for a shortest edit script from both ends at once and
+// returns the start and end of the diagonal run where both searches meet.
+// Both ranges are non-empty and differ in their first and last lines.
+func (matcher *lineMatcher) middleSnake(aLow int, aHigh int, bLow int, bHigh int) (int, int, int, int) {
+	a, b := matcher.a[aLow:aHigh], matcher.b[bLow:bHigh]
+	n, m := len(a), len(b)
+
+	// Diagonals are numbered by x - y, the backward search runs over the
+	// reversed lines so diagonal k forward is delta - k backward
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	forward, backward := matcher.forward, matcher.backward
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && x+backward[offset+c] >= n {
+				return aLow + startX, bLow + startY, aLow + x, bLow + y
+			}
+		}
+
+		for c := -d; c <= d; c += 2 {
+			var x int
+			if c == -d || (c != d && backward[offset+c-1] < backward[offset+c+1]) {
+				x = backward[offset+c+1]
+			} else {
+				x = backward[offset+c-1] + 1
+			}
+
+			y := x - c
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+
+			backward[offset+c] = x
+
+			if k := delta - c; !odd && k >= -d && k <= d && x+forward[offset+k] >= n {
+				return aLow + n - x, bLow + m - y, aLow + n - startX, bLow + m - startY
+			}
+		}
+	}
+
+	// Unreachable, the searches meet once d reaches half the edit distance
+	return aLow, bLow, aLow, bLow
+}
@@ -0,0 +1,182 @@
+package scaffold
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMatchLines(t *testing.T) {
+	a := splitLines("a\nb\nc\na\nb\nb\na\n")
+	b := splitLines("c\nb\na\nb\na\nc\n")
+
+	matches := matchLines(a, b)
+
+	common := 0
+	previous := -1
+	for i, j := range matches {
+		if j < 0 {
+			continue
+		}
+
+		if a[i] != b[j] || j <= previous {
+			t.Fatalf("Invalid match %d -> %d in %v", i, j, matches)
+		}
+
+		previous = j
+		common++
+	}
+
+	if common != 4 {
+		t.Errorf("Expected a longest common subsequence of 4 lines, got %d: %v", common, matches)
+	}
+}
+
+func TestMatchLinesShortest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for run := 0; run < 200; run++ {
+		a := randomLines(random, random.Intn(30))
+		b := randomLines(random, random.Intn(30))
+
+		common := validMatches(t, a, b, matchLines(a, b))
+		if expected := longestCommon(a, b); common != expected {
+			t.Fatalf("Expected a longest common subsequence of %d lines, got %d for %q and %q", expected, common, a, b)
+		}
+	}
+}
+
+func TestMatchLinesRewritten(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 5000; i++ {
+		a = append(a, fmt.Sprintf("old %d\n", i))
+		b = append(b, fmt.Sprintf("new %d\n", i))
+	}
+
+	a[2500] = "shared\n"
+	b[1000] = "shared\n"
+
+	if common := validMatches(t, a, b, matchLines(a, b)); common != 1 {
+		t.Errorf("Expected a single common line, got %d", common)
+	}
+}
+
+func randomLines(random *rand.Rand, count int) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", random.Intn(3)) + "\n"
+	}
+
+	return lines
+}
+
+// validMatches checks that matches pair equal lines in order and returns how
+// many lines are matched.
+func validMatches(t *testing.T, a []string, b []string, matches []int) int {
+	t.Helper()
+
+	common := 0
+	previous := -1
+	for i, j := range matches {
+		if j < 0 {
+			continue
+		}
+
+		if a[i] != b[j] || j <= previous {
+			t.Fatalf("Invalid match %d -> %d", i, j)
+		}
+
+		previous = j
+		common++
+	}
+
+	return common
+}
+
+// longestCommon returns the length of the longest common subsequence of a
+// and b by dynamic programming.
+func longestCommon(a []string, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	return lengths[0][0]
+}
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       string
+		ours       string
+		theirs     string
+		merged     string
+		conflicted bool
+	}{
+		{
+			name:   "unchanged",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nb\nc\n",
+		},
+		{
+			name:   "only template changed",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nB\nc\nd\n",
+			merged: "a\nB\nc\nd\n",
+		},
+		{
+			name:   "separate changes",
+			base:   "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n",
+			ours:   "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello, world\")\n}\n",
+			theirs: "// Code generated by scaffold.\npackage main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n",
+			merged: "// Code generated by scaffold.\npackage main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello, world\")\n}\n",
+		},
+		{
+			name:   "same change on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nx\nc\n",
+			theirs: "a\nx\nc\n",
+			merged: "a\nx\nc\n",
+		},
+		{
+			name:       "conflicting changes",
+			base:       "a\nb\nc\n",
+			ours:       "a\nmine\nc\n",
+			theirs:     "a\ntheirs\nc\n",
+			merged:     "a\n<<<<<<< current\nmine\n=======\ntheirs\n>>>>>>> template\nc\n",
+			conflicted: true,
+		},
+		{
+			name:       "conflict without trailing newline",
+			base:       "a\nb",
+			ours:       "a\nmine",
+			theirs:     "a\ntheirs",
+			merged:     "a\n<<<<<<< current\nmine\n=======\ntheirs\n>>>>>>> template\n",
+			conflicted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicted := merge3(test.base, test.ours, test.theirs)
+
+			if merged != test.merged || conflicted != test.conflicted {
+				t.Errorf("Unexpected merge result (conflicted %t).\nExpected:\n%s\nGot:\n%s", conflicted, test.merged, merged)
+			}
+		})
+	}
+}
@@ -35,7 +35,7 @@ func ModifierSlug(subject string) string {
 
 			// If this isn't the first letter, and there's not already a dash preceding, put a dash before the letter
 			slugLength := len(slug)
-			if i != 0 && slug[slugLength-1] != 45 {
+			if slugLength > 0 && slug[slugLength-1] != 45 {
 				slug = append(slug, 45)
 			}
 
@@ -44,7 +44,7 @@ func ModifierSlug(subject string) string {
 		} else {
 			// any other character, add a dash, if there's not already one preceding
 			slugLength := len(slug)
-			if i != 0 && slug[slugLength-1] != 45 {
+			if slugLength > 0 && slug[slugLength-1] != 45 {
 				slug = append(slug, 45)
 			}
 		}
@@ -52,7 +52,7 @@ func ModifierSlug(subject string) string {
 
 	// if the last char in the slug is a dash, remove it.
 	slugLength := len(slug)
-	if slug[slugLength-1] == 45 {
+	if slugLength > 0 && slug[slugLength-1] == 45 {
 		slug = slug[:slugLength-1]
 	}
 
@@ -73,7 +73,7 @@ func ModifierSnake(subject string) string {
 
 			// If this isn't the first letter, and there's not already an underscore preceding, put it before the letter
 			modifiedLength := len(modified)
-			if i != 0 && modified[modifiedLength-1] != 95 {
+			if modifiedLength > 0 && modified[modifiedLength-1] != 95 {
 				modified = append(modified, 95)
 			}
 
@@ -82,7 +82,7 @@ func ModifierSnake(subject string) string {
 		} else {
 			// any other character, add an underscore, if there's not already one preceding
 			modifiedLength := len(modified)
-			if i != 0 && modified[modifiedLength-1] != 95 {
+			if modifiedLength > 0 && modified[modifiedLength-1] != 95 {
 				modified = append(modified, 95)
 			}
 		}
@@ -90,7 +90,7 @@ func ModifierSnake(subject string) string {
 
 	// if the last char in the modified is an underscore, remove it.
 	modifiedLength := len(modified)
-	if modified[modifiedLength-1] == 95 {
+	if modifiedLength > 0 && modified[modifiedLength-1] == 95 {
 		modified = modified[:modifiedLength-1]
 	}
 
@@ -188,7 +188,7 @@ func ModifierTitle(subject string) string {
 
 			// if not the first char and preceding char was not a space, add space
 			modifiedLength := len(modified)
-			if i != 0 && modified[modifiedLength-1] != 32 {
+			if modifiedLength > 0 && modified[modifiedLength-1] != 32 {
 				modified = append(modified, 32)
 			}
 
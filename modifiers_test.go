@@ -33,6 +33,16 @@ func TestModifierSlug(t *testing.T) {
 	if actual != expected {
 		t.Errorf("Unexpected result from modifier. Expected: %v, Got %v", expected, actual)
 	}
+
+	// Optional tokens without a value are modified too
+	expected = ""
+	input = ""
+
+	actual = ModifierSlug(input)
+
+	if actual != expected {
+		t.Errorf("Unexpected result from modifier. Expected: %v, Got %v", expected, actual)
+	}
 }
 
 func TestModifierSnake(t *testing.T) {
@@ -52,6 +62,16 @@ func TestModifierSnake(t *testing.T) {
 	if actual != expected {
 		t.Errorf("Unexpected result from modifier. Expected: %v, Got %v", expected, actual)
 	}
+
+	// Optional tokens without a value are modified too
+	expected = ""
+	input = ""
+
+	actual = ModifierSnake(input)
+
+	if actual != expected {
+		t.Errorf("Unexpected result from modifier. Expected: %v, Got %v", expected, actual)
+	}
 }
 
 func TestModifierPascal(t *testing.T) {
@@ -109,6 +129,16 @@ func TestModifierTitle(t *testing.T) {
 	if actual != expected {
 		t.Errorf("Unexpected result from modifier. Expected: %v, Got %v", expected, actual)
 	}
+
+	// Optional tokens without a value are modified too
+	expected = ""
+	input = ""
+
+	actual = ModifierTitle(input)
+
+	if actual != expected {
+		t.Errorf("Unexpected result from modifier. Expected: %v, Got %v", expected, actual)
+	}
 }
 
 func TestModifierPlural(t *testing.T) {
@@ -0,0 +1,191 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Output receives everything Make generates. Names are slash separated and
+// relative to the root of the output, which itself is named ".".
+type Output interface {
+	MkdirAll(name string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Exists(name string) (bool, error)
+}
+
+// EditableOutput is an Output whose files can be read back and removed, which
+// Make needs to roll back a failed run.
+type EditableOutput interface {
+	Output
+	ReadFile(name string) ([]byte, error)
+	Remove(name string) error
+}
+
+// DirOutput writes to a directory on the local filesystem.
+type DirOutput struct {
+	Root string
+}
+
+func NewDirOutput(root string) *DirOutput {
+	return &DirOutput{Root: root}
+}
+
+func (out *DirOutput) path(name string) string {
+	return filepath.Join(out.Root, filepath.FromSlash(name))
+}
+
+func (out *DirOutput) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(out.path(name), perm)
+}
+
+// WriteFile writes a file with perm, which unlike os.WriteFile also applies
+// when the file already exists.
+func (out *DirOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := os.WriteFile(out.path(name), data, perm); err != nil {
+		return err
+	}
+
+	return os.Chmod(out.path(name), perm)
+}
+
+func (out *DirOutput) Exists(name string) (bool, error) {
+	_, err := os.Stat(out.path(name))
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (out *DirOutput) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(out.path(name))
+}
+
+func (out *DirOutput) Remove(name string) error {
+	return os.Remove(out.path(name))
+}
+
+// Mode returns the permissions of a file.
+func (out *DirOutput) Mode(name string) (fs.FileMode, error) {
+	info, err := os.Stat(out.path(name))
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Mode().Perm(), nil
+}
+
+type MemoryFile struct {
+	Data []byte
+	Mode fs.FileMode
+}
+
+// MemoryOutput keeps generated files in memory, keyed by their slash separated
+// name, which is mostly useful for tests and for serving generated projects.
+type MemoryOutput struct {
+	Files map[string]*MemoryFile
+	Dirs  map[string]fs.FileMode
+}
+
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{
+		Files: make(map[string]*MemoryFile),
+		Dirs:  map[string]fs.FileMode{".": fs.ModeDir | fs.ModePerm},
+	}
+}
+
+func (out *MemoryOutput) MkdirAll(name string, perm fs.FileMode) error {
+	for dir := path.Clean(name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := out.Files[dir]; ok {
+			return &fs.PathError{Op: "mkdir", Path: dir, Err: fs.ErrExist}
+		}
+
+		if _, ok := out.Dirs[dir]; !ok {
+			out.Dirs[dir] = fs.ModeDir | perm
+		}
+	}
+
+	return nil
+}
+
+func (out *MemoryOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	name = path.Clean(name)
+
+	if _, ok := out.Dirs[name]; ok {
+		return &fs.PathError{Op: "write", Path: name, Err: errors.New("is a directory")}
+	}
+
+	if _, ok := out.Dirs[path.Dir(name)]; !ok {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	}
+
+	out.Files[name] = &MemoryFile{Data: slices.Clone(data), Mode: perm}
+
+	return nil
+}
+
+func (out *MemoryOutput) Exists(name string) (bool, error) {
+	name = path.Clean(name)
+
+	_, isFile := out.Files[name]
+	_, isDir := out.Dirs[name]
+
+	return isFile || isDir, nil
+}
+
+func (out *MemoryOutput) ReadFile(name string) ([]byte, error) {
+	file, ok := out.Files[path.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return slices.Clone(file.Data), nil
+}
+
+func (out *MemoryOutput) Remove(name string) error {
+	name = path.Clean(name)
+
+	if _, ok := out.Files[name]; ok {
+		delete(out.Files, name)
+		return nil
+	}
+
+	if _, ok := out.Dirs[name]; !ok || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+
+	for other := range out.Files {
+		if strings.HasPrefix(other, name+"/") {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+	}
+
+	for other := range out.Dirs {
+		if strings.HasPrefix(other, name+"/") {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+	}
+
+	delete(out.Dirs, name)
+
+	return nil
+}
+
+// Mode returns the permissions of a file.
+func (out *MemoryOutput) Mode(name string) (fs.FileMode, error) {
+	file, ok := out.Files[path.Clean(name)]
+	if !ok {
+		return 0, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return file.Mode.Perm(), nil
+}
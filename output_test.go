@@ -0,0 +1,202 @@
+package scaffold
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func outputTestScaffold(t *testing.T) *Scaffold {
+	t.Helper()
+
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "{{name}}"
+			value = "billing"
+		`)},
+		"{{name}}/main.go": &fstest.MapFile{Data: []byte("package {{name}}\n")},
+		"README.md":        &fstest.MapFile{Data: []byte("# {{name}}\n")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+
+	return scaf
+}
+
+func TestMakeToMemoryOutput(t *testing.T) {
+	scaf := outputTestScaffold(t)
+	out := NewMemoryOutput()
+
+	err := scaf.MakeTo(out)
+	if err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected := map[string]string{
+		"README.md":       "# billing\n",
+		"billing/main.go": "package billing\n",
+	}
+
+	if len(out.Files) != len(expected) {
+		t.Errorf("Expected %d files, got %d", len(expected), len(out.Files))
+	}
+
+	for name, content := range expected {
+		file, ok := out.Files[name]
+		if !ok {
+			t.Errorf("Expected %s to be generated", name)
+			continue
+		}
+
+		if string(file.Data) != content {
+			t.Errorf("Unexpected content for %s. Expected %q, got %q", name, content, string(file.Data))
+		}
+	}
+
+	if _, ok := out.Dirs["billing"]; !ok {
+		t.Errorf("Expected billing directory to be created")
+	}
+}
+
+func TestMakeToArchives(t *testing.T) {
+	expected := map[string]string{
+		"README.md":       "# billing\n",
+		"billing/":        "",
+		"billing/main.go": "package billing\n",
+	}
+
+	t.Run("zip", func(t *testing.T) {
+		var buf bytes.Buffer
+		out := NewZipOutput(&buf)
+
+		if err := outputTestScaffold(t).MakeTo(out); err != nil {
+			t.Fatalf("Failed to make scaffold: %v", err)
+		}
+		if err := out.Close(); err != nil {
+			t.Fatalf("Failed to close archive: %v", err)
+		}
+
+		reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("Failed to read archive: %v", err)
+		}
+
+		actual := make(map[string]string)
+		for _, file := range reader.File {
+			rc, err := file.Open()
+			if err != nil {
+				t.Fatalf("Failed to open %s: %v", file.Name, err)
+			}
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			actual[file.Name] = string(content)
+		}
+
+		assertArchive(t, expected, actual)
+	})
+
+	t.Run("tar.gz", func(t *testing.T) {
+		var buf bytes.Buffer
+		out := NewTarGzOutput(&buf)
+
+		if err := outputTestScaffold(t).MakeTo(out); err != nil {
+			t.Fatalf("Failed to make scaffold: %v", err)
+		}
+		if err := out.Close(); err != nil {
+			t.Fatalf("Failed to close archive: %v", err)
+		}
+
+		gzipReader, err := gzip.NewReader(&buf)
+		if err != nil {
+			t.Fatalf("Failed to read archive: %v", err)
+		}
+
+		reader := tar.NewReader(gzipReader)
+		actual := make(map[string]string)
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			content, _ := io.ReadAll(reader)
+			actual[header.Name] = string(content)
+		}
+
+		assertArchive(t, expected, actual)
+	})
+}
+
+func assertArchive(t *testing.T, expected map[string]string, actual map[string]string) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Errorf("Expected %d archive entries, got %d: %v", len(expected), len(actual), actual)
+	}
+
+	for name, content := range expected {
+		if actual[name] != content {
+			t.Errorf("Unexpected content for %s. Expected %q, got %q", name, content, actual[name])
+		}
+	}
+}
+
+// failingOutput fails to write any file named fail.txt
+type failingOutput struct {
+	*MemoryOutput
+}
+
+func (out failingOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if name == "fail.txt" {
+		return errors.New("write failed")
+	}
+
+	return out.MemoryOutput.WriteFile(name, data, perm)
+}
+
+func TestMakeToRollsBackEditableOutput(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "{{name}}"
+			value = "billing"
+		`)},
+		"existing.txt":    &fstest.MapFile{Data: []byte("{{name}}")},
+		"fail.txt":        &fstest.MapFile{Data: []byte("{{name}}")},
+		"api/{{name}}.go": &fstest.MapFile{Data: []byte("package api\n")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	out := failingOutput{NewMemoryOutput()}
+	out.Files["existing.txt"] = &MemoryFile{Data: []byte("edited"), Mode: 0644}
+
+	if err := scaf.MakeTo(out); err == nil {
+		t.Fatalf("Expected MakeTo to fail")
+	}
+
+	if len(out.Files) != 1 || string(out.Files["existing.txt"].Data) != "edited" {
+		t.Errorf("Expected only the restored existing.txt to remain, got %v", out.Files)
+	}
+
+	if len(out.Dirs) != 1 {
+		t.Errorf("Expected created directories to be removed, got %v", out.Dirs)
+	}
+}
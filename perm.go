@@ -0,0 +1,56 @@
+package scaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+)
+
+const (
+	defaultFilePerm fs.FileMode = 0644
+	defaultDirPerm  fs.FileMode = fs.ModePerm
+)
+
+// parsePerm parses an octal permission such as "0755" from a path rule.
+func parsePerm(mode string) (fs.FileMode, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || perm > uint64(fs.ModePerm) {
+		return 0, fmt.Errorf("invalid mode %q", mode)
+	}
+
+	return fs.FileMode(perm), nil
+}
+
+// permFor returns the permissions to create a template path with: those of
+// the template file or directory, unless the last matching path rule with a
+// mode overrides them. Directories are created subject to the umask. Like the
+// contents, the permissions of a symlink are those of its target.
+func (scaf *Scaffold) permFor(relativePath string) (fs.FileMode, error) {
+	info, err := fs.Stat(scaf.FS, relativePath)
+	if err != nil {
+		return 0, err
+	}
+
+	perm := info.Mode().Perm()
+
+	// Templates without permissions, like an fstest.MapFS, get the defaults.
+	// Otherwise the owner keeps write access so the project can be updated.
+	switch {
+	case perm == 0 && info.IsDir():
+		perm = defaultDirPerm
+	case perm == 0:
+		perm = defaultFilePerm
+	case info.IsDir():
+		perm |= 0700
+	default:
+		perm |= 0600
+	}
+
+	for _, rule := range scaf.Config.Paths {
+		if rule.Mode != "" && rule.matches(relativePath) {
+			perm, _ = parsePerm(rule.Mode)
+		}
+	}
+
+	return perm, nil
+}
@@ -0,0 +1,169 @@
+package scaffold
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPermissions(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[path]]
+			path = "config/secrets.env"
+			mode = "0600"
+
+			[[path]]
+			path = "bin"
+			mode = "0750"
+		`)},
+		"scripts":              &fstest.MapFile{Mode: fs.ModeDir | 0755},
+		"scripts/bootstrap.sh": &fstest.MapFile{Data: []byte("#!/bin/sh\n"), Mode: 0755},
+		"scripts/readonly.txt": &fstest.MapFile{Data: []byte("readonly"), Mode: 0444},
+		"config/secrets.env":   &fstest.MapFile{Data: []byte("TOKEN=\n"), Mode: 0644},
+		"bin/run":              &fstest.MapFile{Data: []byte("run")},
+		"README.md":            &fstest.MapFile{Data: []byte("# readme")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expectedFiles := map[string]fs.FileMode{
+		"scripts/bootstrap.sh": 0755,
+		"scripts/readonly.txt": 0644,
+		"config/secrets.env":   0600,
+		"bin/run":              0750,
+		"README.md":            0644,
+	}
+
+	for name, perm := range expectedFiles {
+		if file := out.Files[name]; file == nil || file.Mode != perm {
+			t.Errorf("Unexpected mode for %s. Expected %v, got %+v", name, perm, file)
+		}
+	}
+
+	expectedDirs := map[string]fs.FileMode{
+		"scripts": fs.ModeDir | 0755,
+		// Directories an fstest.MapFS makes up are read-only
+		"config": fs.ModeDir | 0755,
+		"bin":    fs.ModeDir | 0750,
+	}
+
+	for name, mode := range expectedDirs {
+		if out.Dirs[name] != mode {
+			t.Errorf("Unexpected mode for %s. Expected %v, got %v", name, mode, out.Dirs[name])
+		}
+	}
+
+	// On disk the umask applies on top
+	destDir := t.TempDir()
+
+	if err := scaf.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(destDir, "scripts", "bootstrap.sh"))
+	if err != nil {
+		t.Fatalf("Failed to stat generated file: %v", err)
+	}
+
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected bootstrap.sh to be executable, got %v", info.Mode())
+	}
+
+	info, err = os.Stat(filepath.Join(destDir, "bin"))
+	if err != nil {
+		t.Fatalf("Failed to stat generated dir: %v", err)
+	}
+
+	if info.Mode().Perm()&0007 != 0 {
+		t.Errorf("Expected bin to be closed to others, got %v", info.Mode())
+	}
+}
+
+func TestInvalidMode(t *testing.T) {
+	for _, mode := range []string{"rwx", "0999", "01777"} {
+		if _, err := parseConfig([]byte("[[path]]\npath = \"bin\"\nmode = \"" + mode + "\"\n")); err == nil {
+			t.Errorf("Expected an error for mode %q", mode)
+		}
+	}
+}
+
+func TestPermissionsOverwrite(t *testing.T) {
+	configContent := `
+		[[path]]
+		path = "secrets.env"
+		mode = "0600"
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"run.sh":      "#!/bin/sh\n",
+		"secrets.env": "TOKEN=\n",
+		"real.txt":    "real",
+	})
+
+	if err := os.Chmod(filepath.Join(templateDir, "run.sh"), 0755); err != nil {
+		t.Fatalf("Failed to chmod template file: %v", err)
+	}
+
+	// A symlink takes the mode of its target rather than 0777
+	if err := os.Symlink("real.txt", filepath.Join(templateDir, "link.txt")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	destDir := t.TempDir()
+
+	for _, name := range []string{"run.sh", "secrets.env"} {
+		if err := os.WriteFile(filepath.Join(destDir, name), []byte("edited"), 0644); err != nil {
+			t.Fatalf("Failed to write existing file: %v", err)
+		}
+	}
+
+	if err := scaf.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected := map[string]fs.FileMode{
+		"run.sh":      0755,
+		"secrets.env": 0600,
+		"link.txt":    0644,
+	}
+
+	for name, perm := range expected {
+		info, err := os.Stat(filepath.Join(destDir, name))
+		if err != nil {
+			t.Fatalf("Failed to stat generated file: %v", err)
+		}
+
+		if info.Mode().Perm() != perm {
+			t.Errorf("Unexpected mode for %s. Expected %v, got %v", name, perm, info.Mode().Perm())
+		}
+	}
+
+	out := NewMemoryOutput()
+	out.Files["run.sh"] = &MemoryFile{Data: []byte("edited"), Mode: 0644}
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	if mode := out.Files["run.sh"].Mode; mode != 0755 {
+		t.Errorf("Unexpected mode for overwritten run.sh in memory. Expected 0755, got %v", mode)
+	}
+}
@@ -0,0 +1,288 @@
+package scaffold
+
+import (
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+type OperationType string
+
+const (
+	OpMkdir     OperationType = "mkdir"
+	OpCreate    OperationType = "create"
+	OpOverwrite OperationType = "overwrite"
+	OpSkip      OperationType = "skip"
+	OpInject    OperationType = "inject"
+	OpPrompt    OperationType = "prompt"
+)
+
+// Operation describes a single change Make would apply to the destination.
+type Operation struct {
+	Type OperationType `json:"type"`
+	Path string        `json:"path"`
+	Size int           `json:"size"`
+
+	name     string
+	contents []byte
+	perm     fs.FileMode
+}
+
+// Plan walks the template and renders every path and file exactly like Make,
+// but only returns the operations it would perform without writing anything.
+// Existing files under the prompt conflict policy are planned as OpPrompt,
+// since the OnConflict callback is only asked by Make.
+func (scaf *Scaffold) Plan(destination string) ([]Operation, error) {
+	return scaf.plan(NewDirOutput(destination), destination, true, true)
+}
+
+// PlanTo is Plan for an arbitrary Output. Operation paths are relative to the
+// root of the output.
+func (scaf *Scaffold) PlanTo(out Output) ([]Operation, error) {
+	return scaf.plan(out, "", true, true)
+}
+
+// plan renders the template against out. Operation paths are reported joined
+// to root, which is empty when out is not a directory on disk. Inject rules
+// only apply to files already in out when injectExisting is set, and a dry
+// run plans conflicts to prompt for instead of asking the OnConflict callback.
+func (scaf *Scaffold) plan(out Output, root string, injectExisting bool, dryRun bool) ([]Operation, error) {
+	if err := scaf.Validate(); err != nil {
+		return nil, err
+	}
+
+	tokens, err := scaf.resolveTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	answers := scaf.answers(tokens)
+
+	ignore, err := scaf.ignoreRules()
+	if err != nil {
+		return nil, err
+	}
+
+	planner := &planner{scaf: scaf, out: out, root: root, ignore: ignore, injectExisting: injectExisting, dryRun: dryRun}
+
+	rootExists, err := out.Exists(".")
+	if err != nil {
+		return nil, err
+	}
+
+	if !rootExists {
+		planner.operations = append(planner.operations, Operation{Type: OpMkdir, Path: outputPath(root, "."), name: ".", perm: defaultDirPerm})
+	}
+
+	if err := planner.walk(".", nil); err != nil {
+		return nil, err
+	}
+
+	if err := planner.planInjects(scaf.replacerFor(tokens)); err != nil {
+		return nil, err
+	}
+
+	operations := planner.operations
+
+	if scaf.AnswersFile != "" {
+		contents, err := toml.Marshal(answers)
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err := out.Exists(scaf.AnswersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		operation := Operation{
+			Type:     OpCreate,
+			Path:     outputPath(root, scaf.AnswersFile),
+			Size:     len(contents),
+			name:     scaf.AnswersFile,
+			contents: contents,
+			perm:     defaultFilePerm,
+		}
+
+		if exists {
+			operation.Type = OpOverwrite
+		}
+
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+// planner collects the operations for rendering a template against out.
+type planner struct {
+	scaf           *Scaffold
+	out            Output
+	root           string
+	ignore         ignoreRules
+	injectExisting bool
+	dryRun         bool
+	operations     []Operation
+}
+
+// walk plans the template tree at dir, with the tokens named in items set to
+// the list items being repeated. Repeated paths below dir are walked again
+// once for every item.
+func (planner *planner) walk(dir string, items map[string]string) error {
+	scaf := planner.scaf
+
+	tokens, err := scaf.resolveItems(items)
+	if err != nil {
+		return err
+	}
+
+	values := tokenValues(tokens)
+	replacer := scaf.replacerFor(tokens)
+
+	return fs.WalkDir(scaf.FS, dir, func(path string, info fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if info.Name() == configFileName || info.Name() == ignoreFileName {
+			return nil
+		}
+
+		if path == "." {
+			return nil
+		}
+
+		included, err := scaf.included(path, values)
+		if err != nil {
+			return err
+		}
+
+		if !included || planner.ignore.ignored(path, info.IsDir()) {
+			if info.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if rule, ok := scaf.repeatRule(path, items); ok {
+			list, err := repeatItems(rule, values)
+			if err != nil {
+				return err
+			}
+
+			for _, item := range list {
+				if err := planner.walk(path, withItem(items, rule.Item, item)); err != nil {
+					return err
+				}
+			}
+
+			if info.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		return planner.visit(path, info, replacer, values)
+	})
+}
+
+// visit plans a single template directory or file.
+func (planner *planner) visit(path string, info fs.DirEntry, replacer *replacer, values map[string]any) error {
+	scaf, out, root := planner.scaf, planner.out, planner.root
+
+	relativePath := replacer.replace(path, path)
+
+	exists, err := out.Exists(relativePath)
+	if err != nil {
+		return err
+	}
+
+	perm, err := scaf.permFor(path)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if !exists {
+			planner.operations = append(planner.operations, Operation{Type: OpMkdir, Path: outputPath(root, relativePath), name: relativePath, perm: perm})
+		}
+
+		return nil
+	}
+
+	contents, err := fs.ReadFile(scaf.FS, path)
+	if err != nil {
+		return err
+	}
+
+	stringcontents := string(contents)
+
+	if scaf.renderMode(path) == RenderGoTemplate {
+		stringcontents, err = scaf.executeTemplate(path, stringcontents, values)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", path, err)
+		}
+
+		relativePath = strings.TrimSuffix(relativePath, goTemplateSuffix)
+
+		exists, err = out.Exists(relativePath)
+		if err != nil {
+			return err
+		}
+	} else {
+		stringcontents = replacer.replace(stringcontents, path)
+	}
+
+	operation := Operation{
+		Type:     OpCreate,
+		Path:     outputPath(root, relativePath),
+		Size:     len(stringcontents),
+		name:     relativePath,
+		contents: []byte(stringcontents),
+		perm:     perm,
+	}
+
+	if exists {
+		policy, err := scaf.resolveConflict(path, operation.Path, !planner.dryRun)
+		if err != nil {
+			return err
+		}
+
+		switch policy {
+		case ConflictOverwrite:
+			operation.Type = OpOverwrite
+		case ConflictSkip:
+			operation.Type = OpSkip
+		case ConflictPrompt:
+			operation.Type = OpPrompt
+		case ConflictNew:
+			operation.name += conflictNewSuffix
+			operation.Path += conflictNewSuffix
+
+			newExists, err := out.Exists(operation.name)
+			if err != nil {
+				return err
+			}
+
+			if newExists {
+				operation.Type = OpOverwrite
+			}
+		}
+	}
+
+	planner.operations = append(planner.operations, operation)
+
+	return nil
+}
+
+func outputPath(root string, name string) string {
+	if root == "" {
+		return name
+	}
+
+	return filepath.Join(root, filepath.FromSlash(name))
+}
@@ -0,0 +1,110 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	// Create a temporary directory for test templates
+	tmpDir, err := os.MkdirTemp("", "scaffold-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Create test template structure
+	templateDir := filepath.Join(tmpDir, "template")
+	err = os.MkdirAll(filepath.Join(templateDir, "{{name}}"), 0755)
+	if err != nil {
+		t.Fatalf("Failed to create template dir: %v", err)
+	}
+
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+	`
+	err = os.WriteFile(filepath.Join(templateDir, "scaffold.toml"), []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(templateDir, "{{name}}", "{{name}}.go"), []byte("package {{name}}\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(templateDir, "README.md"), []byte("# {{name}}\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.RegisterTokenValue("{{name}}", "myapp")
+	scaf.AnswersFile = ""
+
+	// An existing file in the destination should be planned as an overwrite
+	destDir := filepath.Join(tmpDir, "output")
+	err = os.MkdirAll(destDir, 0755)
+	if err != nil {
+		t.Fatalf("Failed to create output dir: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(destDir, "README.md"), []byte("existing"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write existing file: %v", err)
+	}
+
+	operations, err := scaf.Plan(destDir)
+	if err != nil {
+		t.Fatalf("Failed to plan scaffold: %v", err)
+	}
+
+	expected := []Operation{
+		{Type: OpOverwrite, Path: destDir + "/README.md", Size: len("# myapp\n")},
+		{Type: OpMkdir, Path: destDir + "/myapp"},
+		{Type: OpCreate, Path: destDir + "/myapp/myapp.go", Size: len("package myapp\n")},
+	}
+
+	if len(operations) != len(expected) {
+		t.Fatalf("Unexpected number of operations. Expected %d, got %d: %+v", len(expected), len(operations), operations)
+	}
+
+	for i := range expected {
+		if operations[i].Type != expected[i].Type || operations[i].Path != expected[i].Path || operations[i].Size != expected[i].Size {
+			t.Errorf("Unexpected operation %d. Expected %+v, got %+v", i, expected[i], operations[i])
+		}
+	}
+
+	// Nothing should have been written
+	if _, err := os.Stat(filepath.Join(destDir, "myapp")); !os.IsNotExist(err) {
+		t.Errorf("Plan created a directory in the destination")
+	}
+
+	existing, err := os.ReadFile(filepath.Join(destDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read existing file: %v", err)
+	}
+	if string(existing) != "existing" {
+		t.Errorf("Plan modified an existing file")
+	}
+
+	// Planning must not consume the token state Make relies on
+	err = scaf.Make(destDir)
+	if err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	generatedContent, err := os.ReadFile(filepath.Join(destDir, "myapp", "myapp.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if string(generatedContent) != "package myapp\n" {
+		t.Errorf("Unexpected generated content: %s", string(generatedContent))
+	}
+}
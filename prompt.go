@@ -0,0 +1,138 @@
+package scaffold
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Prompt asks for a value for every input token that has not been registered
+// yet, reading answers line by line from in. Empty answers take the token's
+// default, invalid answers are asked again, and the values derived from each
+// answer through bound tokens are previewed once it is accepted.
+func (scaf *Scaffold) Prompt(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+
+	for _, token := range scaf.Inputs() {
+		if _, ok := scaf.TokenValueMap[token.Name]; ok {
+			continue
+		}
+
+		value, err := scaf.promptToken(reader, out, token)
+		if err != nil {
+			return err
+		}
+
+		scaf.RegisterTokenValue(token.Name, value)
+
+		if err := scaf.previewDerived(out, token.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (scaf *Scaffold) promptToken(reader *bufio.Reader, out io.Writer, token Token) (string, error) {
+	if token.Description != "" {
+		fmt.Fprintln(out, token.Description)
+	}
+
+	label := token.Prompt
+	if label == "" {
+		label = token.Name
+	}
+
+	switch {
+	case len(token.Choices) > 0:
+		label += " (" + strings.Join(token.Choices, "|") + ")"
+	case token.Example != "":
+		label += " (e.g. " + token.Example + ")"
+	case token.Type == TypeBool:
+		label += " (true|false)"
+	case token.Type == TypeList:
+		label += " (comma separated)"
+	}
+
+	if token.Default != "" {
+		label += " [" + token.Default + "]"
+	}
+
+	for {
+		fmt.Fprint(out, label+": ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return "", &TokenError{Token: token.Name, Err: err}
+		}
+
+		value := strings.TrimSpace(line)
+		if value == "" {
+			value = token.Default
+		}
+
+		if value == "" {
+			if !token.IsRequired() {
+				return "", nil
+			}
+
+			fmt.Fprintln(out, "  a value is required")
+			continue
+		}
+
+		if err := token.CheckValue(value); err != nil {
+			fmt.Fprintln(out, "  "+err.Error())
+			continue
+		}
+
+		return value, nil
+	}
+}
+
+// previewDerived prints the values of every token that depends on name, so
+// users can see what their answer turns into before moving on.
+func (scaf *Scaffold) previewDerived(out io.Writer, name string) error {
+	tokens, err := scaf.resolveTokens()
+	if err != nil {
+		return err
+	}
+
+	previewed := []string{name}
+
+	for _, token := range tokens {
+		if slices.Contains(previewed, token.Name) || !isBoundTo(tokens, token, name) {
+			continue
+		}
+
+		previewed = append(previewed, token.Name)
+		fmt.Fprintf(out, "  %s = %s\n", token.Name, token.Value)
+	}
+
+	return nil
+}
+
+// isBoundTo reports whether token is bound to name, directly or through a
+// chain of other bound tokens. Bindings are assumed to be free of cycles.
+func isBoundTo(tokens []Token, token Token, name string) bool {
+	for token.Token != "" {
+		if token.Token == name {
+			return true
+		}
+
+		parent, ok := findToken(tokens, token.Token)
+		if !ok {
+			return false
+		}
+
+		token = parent
+	}
+
+	return false
+}
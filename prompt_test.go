@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrompt(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "service"
+		prompt = "Service name"
+		description = "Name of the service"
+		pattern = "^[a-z][a-zA-Z0-9]+$"
+
+		[[token]]
+		name = "Service"
+		token = "service"
+		modifiers = ["pascal"]
+
+		[[token]]
+		name = "service_snake"
+		token = "Service"
+		modifiers = ["snake"]
+
+		[[token]]
+		name = "db"
+		choices = ["postgres", "mysql", "sqlite"]
+		default = "postgres"
+
+		[[token]]
+		name = "owner"
+
+		[[token]]
+		name = "notes"
+		required = false
+	`
+	templateDir := createTemplate(t, configContent, nil)
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	// Already registered values are not asked for
+	scaf.RegisterTokenValue("owner", "platform")
+
+	// An invalid name, a valid one, the default db and no notes
+	in := strings.NewReader("Billing\nbillingApi\n\n\n")
+	var out bytes.Buffer
+
+	err = scaf.Prompt(in, &out)
+	if err != nil {
+		t.Fatalf("Failed to prompt: %v", err)
+	}
+
+	expectedValues := map[string]string{
+		"service": "billingApi",
+		"db":      "postgres",
+		"owner":   "platform",
+		"notes":   "",
+	}
+
+	for name, value := range expectedValues {
+		if scaf.TokenValueMap[name] != value {
+			t.Errorf("Unexpected value for %s. Expected %q, got %q", name, value, scaf.TokenValueMap[name])
+		}
+	}
+
+	expectedOutput := `Name of the service
+Service name: ` + `  token "service": invalid value "Billing": must match pattern ^[a-z][a-zA-Z0-9]+$
+Service name:   Service = BillingApi
+  service_snake = billing_api
+db (postgres|mysql|sqlite) [postgres]: notes: `
+
+	if out.String() != expectedOutput {
+		t.Errorf("Unexpected prompt output.\nExpected:\n%s\nGot:\n%s", expectedOutput, out.String())
+	}
+}
+
+func TestPromptUnexpectedEOF(t *testing.T) {
+	templateDir := createTemplate(t, `
+		[[token]]
+		name = "service"
+	`, nil)
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := scaf.Prompt(strings.NewReader(""), &out); err == nil {
+		t.Errorf("Expected an error when input ends before all values are given")
+	}
+}
@@ -0,0 +1,48 @@
+package scaffold
+
+import (
+	"fmt"
+	"maps"
+	"path"
+)
+
+// repeatRule returns the rule repeating a template path, unless the path is
+// already being repeated for that rule's item.
+func (scaf *Scaffold) repeatRule(relativePath string, items map[string]string) (RepeatRule, bool) {
+	for _, rule := range scaf.Config.Repeats {
+		if _, ok := items[rule.Item]; ok {
+			continue
+		}
+
+		if path.Clean(rule.Path) == relativePath {
+			return rule, true
+		}
+	}
+
+	return RepeatRule{}, false
+}
+
+// repeatItems returns the items a rule repeats over.
+func repeatItems(rule RepeatRule, values map[string]any) ([]string, error) {
+	switch value := values[rule.Over].(type) {
+	case []string:
+		return value, nil
+	case string:
+		return splitList(value), nil
+	case nil:
+		return nil, fmt.Errorf("repeat %q: unknown token %q", rule.Path, rule.Over)
+	}
+
+	return nil, fmt.Errorf("repeat %q: token %q is not a list", rule.Path, rule.Over)
+}
+
+func withItem(items map[string]string, name string, item string) map[string]string {
+	items = maps.Clone(items)
+	if items == nil {
+		items = make(map[string]string)
+	}
+
+	items[name] = item
+
+	return items
+}
@@ -0,0 +1,131 @@
+package scaffold
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestRepeat(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "entities"
+			type = "list"
+
+			[[token]]
+			name = "entity"
+			token = "entities"
+			modifiers = ["snake"]
+
+			[[token]]
+			name = "Entity"
+			token = "entity"
+			modifiers = ["pascal"]
+
+			[[token]]
+			name = "Entities"
+			token = "entity"
+			modifiers = ["pascal", "plural"]
+			priority = 1
+
+			[[token]]
+			name = "envs"
+			type = "list"
+
+			[[repeat]]
+			path = "handlers/entity.go"
+			over = "entities"
+			item = "entity"
+
+			[[repeat]]
+			path = "deploy/env"
+			over = "envs"
+			item = "env"
+
+			[[include]]
+			path = "handlers/entity.go"
+			when = "entity != 'audit_log'"
+		`)},
+		"handlers/entity.go":     &fstest.MapFile{Data: []byte("func List(Entities) []Entity { return nil } // entity\n")},
+		"routes.go":              &fstest.MapFile{Data: []byte("// entities\n")},
+		"deploy/env/values.yaml": &fstest.MapFile{Data: []byte("stage: env\n")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	// Item tokens that are not declared are bound to their list
+	if token, err := scaf.GetTokenByName("env"); err != nil || token.Token != "envs" || token.IsInput() {
+		t.Errorf("Expected env to be bound to envs, got %+v", token)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("entities", "User, OrderItem, AuditLog")
+	scaf.RegisterTokenValue("envs", "staging,production")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected := map[string]string{
+		"handlers/user.go":              "func List(Users) []User { return nil } // user\n",
+		"handlers/order_item.go":        "func List(OrderItems) []OrderItem { return nil } // order_item\n",
+		"routes.go":                     "// User, OrderItem, AuditLog\n",
+		"deploy/staging/values.yaml":    "stage: staging\n",
+		"deploy/production/values.yaml": "stage: production\n",
+	}
+
+	if len(out.Files) != len(expected) {
+		t.Errorf("Unexpected files: %v", out.Files)
+	}
+
+	for name, content := range expected {
+		file, ok := out.Files[name]
+		if !ok {
+			t.Errorf("Expected %s to be generated", name)
+			continue
+		}
+
+		if string(file.Data) != content {
+			t.Errorf("Unexpected content in %s.\nExpected:\n%s\nGot:\n%s", name, content, file.Data)
+		}
+	}
+}
+
+func TestRepeatErrors(t *testing.T) {
+	if _, err := parseConfig([]byte(`
+		[[repeat]]
+		path = "handlers/entity.go"
+		over = "entities"
+	`)); err == nil {
+		t.Errorf("Expected an error for a repeat without an item")
+	}
+
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "replicas"
+			type = "int"
+			default = "2"
+
+			[[repeat]]
+			path = "replica.txt"
+			over = "replicas"
+			item = "replica"
+		`)},
+		"replica.txt": &fstest.MapFile{Data: []byte("replica")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	if err := scaf.MakeTo(NewMemoryOutput()); err == nil {
+		t.Errorf("Expected an error repeating over an int")
+	}
+}
@@ -0,0 +1,292 @@
+package scaffold
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MatchSubstring  = "substring"
+	MatchWord       = "word"
+	MatchIdentifier = "identifier"
+)
+
+func validMatch(match string) bool {
+	switch match {
+	case "", MatchSubstring, MatchWord, MatchIdentifier:
+		return true
+	}
+
+	return false
+}
+
+// replacer replaces every token in a single pass. At each position the
+// longest token name that matches wins, with the higher Priority breaking
+// ties between tokens of the same name, and inserted values are never
+// scanned again. Localized tokens only apply to some paths, so a trie is
+// built once for every distinct set of tokens that applies.
+type replacer struct {
+	replacements []replacement
+	tries        map[string]*trie
+}
+
+type replacement struct {
+	token Token
+	old   string
+	new   string
+}
+
+// newReplacer prepares the replacement of tokens. With delimiters, tokens
+// only match between them, and escaped tokens are replaced by the delimited
+// token name itself.
+func newReplacer(tokens []Token, delimiters Delimiters) *replacer {
+	var replacements []replacement
+
+	for _, token := range tokens {
+		if token.Name == "" {
+			continue
+		}
+
+		if delimiters.Open == "" {
+			replacements = append(replacements, replacement{token: token, old: token.Name, new: token.Value})
+			continue
+		}
+
+		delimited := delimiters.Open + token.Name + delimiters.Close
+
+		replacements = append(replacements,
+			replacement{token: token, old: delimited, new: token.Value},
+			replacement{token: token, old: delimiters.escape() + delimited, new: delimited},
+		)
+	}
+
+	slices.SortStableFunc(replacements, func(a, b replacement) int {
+		if byLength := cmp.Compare(len(b.old), len(a.old)); byLength != 0 {
+			return byLength
+		}
+
+		return cmp.Compare(b.token.Priority, a.token.Priority)
+	})
+
+	return &replacer{
+		replacements: replacements,
+		tries:        make(map[string]*trie),
+	}
+}
+
+// replace replaces tokens in subject, where path is the slash separated
+// template path the subject belongs to and decides which localized tokens apply.
+func (r *replacer) replace(subject string, path string) string {
+	var applied []int
+	var key strings.Builder
+
+	for i, replacement := range r.replacements {
+		if replacement.token.appliesTo(path) {
+			applied = append(applied, i)
+			key.WriteString(strconv.Itoa(i))
+			key.WriteByte(',')
+		}
+	}
+
+	if len(applied) == 0 {
+		return subject
+	}
+
+	tokenTrie, ok := r.tries[key.String()]
+	if !ok {
+		tokenTrie = newTrie(r.replacements, applied)
+		r.tries[key.String()] = tokenTrie
+	}
+
+	return tokenTrie.replace(subject)
+}
+
+// trie matches the names of a set of replacements byte by byte. The root
+// is a table, so positions where no name starts are skipped quickly.
+type trie struct {
+	replacements []replacement
+	root         [256]int32
+	nodes        []trieNode
+}
+
+type trieNode struct {
+	labels   []byte
+	children []int32
+	// matches are the replacements ending at this node, in order of priority
+	matches []int
+}
+
+func (node *trieNode) next(char byte) int32 {
+	for i, label := range node.labels {
+		if label == char {
+			return node.children[i]
+		}
+	}
+
+	return 0
+}
+
+func newTrie(replacements []replacement, applied []int) *trie {
+	t := &trie{replacements: replacements, nodes: []trieNode{{}}}
+
+	for _, i := range applied {
+		old := replacements[i].old
+
+		node := int32(0)
+		for j := 0; j < len(old); j++ {
+			node = t.child(node, old[j])
+		}
+
+		t.nodes[node].matches = append(t.nodes[node].matches, i)
+	}
+
+	return t
+}
+
+// child returns the child of node for char, adding it if needed.
+func (t *trie) child(node int32, char byte) int32 {
+	if node == 0 && t.root[char] != 0 {
+		return t.root[char]
+	}
+
+	if next := t.nodes[node].next(char); next != 0 {
+		return next
+	}
+
+	next := int32(len(t.nodes))
+	t.nodes = append(t.nodes, trieNode{})
+
+	if node == 0 {
+		t.root[char] = next
+		return next
+	}
+
+	t.nodes[node].labels = append(t.nodes[node].labels, char)
+	t.nodes[node].children = append(t.nodes[node].children, next)
+
+	return next
+}
+
+func (t *trie) replace(subject string) string {
+	var result strings.Builder
+	last := 0
+
+	for i := 0; i < len(subject); {
+		match := t.longestMatch(subject, i)
+		if match < 0 {
+			i++
+			continue
+		}
+
+		if last == 0 {
+			result.Grow(len(subject))
+		}
+
+		replacement := &t.replacements[match]
+
+		result.WriteString(subject[last:i])
+		result.WriteString(replacement.new)
+
+		i += len(replacement.old)
+		last = i
+	}
+
+	if last == 0 {
+		return subject
+	}
+
+	result.WriteString(subject[last:])
+
+	return result.String()
+}
+
+// longestMatch returns the longest replacement matching subject at start
+// whose token stands on its own there, or -1.
+func (t *trie) longestMatch(subject string, start int) int {
+	node := t.root[subject[start]]
+	if node == 0 {
+		return -1
+	}
+
+	var buffer [8]int32
+	candidates := buffer[:0]
+
+	for i := start + 1; ; i++ {
+		if len(t.nodes[node].matches) > 0 {
+			candidates = append(candidates, node)
+		}
+
+		if i == len(subject) {
+			break
+		}
+
+		next := t.nodes[node].next(subject[i])
+		if next == 0 {
+			break
+		}
+
+		node = next
+	}
+
+	for j := len(candidates) - 1; j >= 0; j-- {
+		for _, match := range t.nodes[candidates[j]].matches {
+			replacement := &t.replacements[match]
+			if replacement.token.bounded(subject, start, start+len(replacement.old)) {
+				return match
+			}
+		}
+	}
+
+	return -1
+}
+
+// bounded reports whether an occurrence of the token in subject between
+// start and end stands on its own according to the token's match mode. Words
+// are bounded by anything but letters and digits, identifiers also by
+// underscores.
+func (token Token) bounded(subject string, start int, end int) bool {
+	var isPart func(rune) bool
+
+	switch token.Match {
+	case MatchWord:
+		isPart = func(char rune) bool {
+			return unicode.IsLetter(char) || unicode.IsDigit(char)
+		}
+	case MatchIdentifier:
+		isPart = func(char rune) bool {
+			return char == '_' || unicode.IsLetter(char) || unicode.IsDigit(char)
+		}
+	default:
+		return true
+	}
+
+	if before, size := utf8.DecodeLastRuneInString(subject[:start]); size > 0 && isPart(before) {
+		return false
+	}
+
+	if after, size := utf8.DecodeRuneInString(subject[end:]); size > 0 && isPart(after) {
+		return false
+	}
+
+	return true
+}
+
+// appliesTo reports whether a token is replaced in a template path, which is
+// any path unless the token is localized.
+func (token Token) appliesTo(path string) bool {
+	if token.Localize == nil {
+		return true
+	}
+
+	for _, tokenPath := range token.Localize {
+		if strings.HasPrefix(path, tokenPath) {
+			return true
+		}
+	}
+
+	return false
+}
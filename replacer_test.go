@@ -0,0 +1,176 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReplacer(t *testing.T) {
+	tokens := []Token{
+		{Name: "name", Value: "billing"},
+		{Name: "Name", Value: "Billing"},
+		{Name: "name_plural", Value: "billings"},
+		// The value contains another token's name and must not be replaced again
+		{Name: "service", Value: "name-service"},
+		{Name: "db", Value: "postgres"},
+		{Name: "db", Value: "sqlite", Localize: []string{"test"}, Priority: 1},
+		{Name: "local", Value: "here", Localize: []string{"cmd"}},
+	}
+
+	replacer := newReplacer(tokens, Delimiters{})
+
+	tests := []struct {
+		subject  string
+		path     string
+		expected string
+	}{
+		{subject: "name Name name_plural", path: "main.go", expected: "billing Billing billings"},
+		{subject: "service/name", path: "main.go", expected: "name-service/billing"},
+		{subject: "db local", path: "main.go", expected: "postgres local"},
+		{subject: "db local", path: "cmd/main.go", expected: "postgres here"},
+		{subject: "db local", path: "test/main_test.go", expected: "sqlite local"},
+		{subject: "nothing to see", path: "main.go", expected: "nothing to see"},
+	}
+
+	for _, test := range tests {
+		if replaced := replacer.replace(test.subject, test.path); replaced != test.expected {
+			t.Errorf("Unexpected replacement of %q in %s. Expected %q, got %q", test.subject, test.path, test.expected, replaced)
+		}
+	}
+
+	// One replacer for each set of applying tokens
+	if len(replacer.tries) != 3 {
+		t.Errorf("Expected 3 tries to be built, got %d", len(replacer.tries))
+	}
+}
+
+// replaceSequential is the previous approach, one ReplaceAll per token in
+// priority order, kept to benchmark against.
+func replaceSequential(tokens []Token, subject string) string {
+	for _, token := range tokens {
+		subject = strings.ReplaceAll(subject, token.Name, token.Value)
+	}
+
+	return subject
+}
+
+func benchmarkInput(tokenCount int) ([]Token, string) {
+	var tokens []Token
+	var content strings.Builder
+
+	for i := range tokenCount {
+		tokens = append(tokens, Token{Name: fmt.Sprintf("token%03d", i), Value: fmt.Sprintf("value%d", i)})
+	}
+
+	for i := range 2000 {
+		fmt.Fprintf(&content, "func handler%d() { return token%03d.Call() }\n", i, i%tokenCount)
+	}
+
+	return tokens, content.String()
+}
+
+func BenchmarkReplacer(b *testing.B) {
+	for _, tokenCount := range []int{5, 50, 500} {
+		tokens, content := benchmarkInput(tokenCount)
+
+		b.Run(fmt.Sprintf("single-pass/%d", tokenCount), func(b *testing.B) {
+			replacer := newReplacer(tokens, Delimiters{})
+			b.SetBytes(int64(len(content)))
+
+			for range b.N {
+				replacer.replace(content, "main.go")
+			}
+		})
+
+		b.Run(fmt.Sprintf("replace-all/%d", tokenCount), func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+
+			for range b.N {
+				replaceSequential(tokens, content)
+			}
+		})
+	}
+}
+
+func TestDelimiters(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[delimiters]
+			open = "__"
+			close = "__"
+
+			[[token]]
+			name = "foo"
+			modifiers = ["pascal"]
+		`)},
+		"__foo__/football.go": &fstest.MapFile{Data: []byte("type __foo__ struct{} // foo, foobar, \\__foo__, \\__bar__\n")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("foo", "widget")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	file, ok := out.Files["Widget/football.go"]
+	if !ok {
+		t.Fatalf("Expected Widget/football.go to be generated, got %v", out.Files)
+	}
+
+	expected := "type Widget struct{} // foo, foobar, __foo__, \\__bar__\n"
+	if string(file.Data) != expected {
+		t.Errorf("Unexpected content.\nExpected:\n%s\nGot:\n%s", expected, file.Data)
+	}
+
+	replacer := newReplacer([]Token{{Name: "name", Value: "billing"}}, Delimiters{Open: "{{", Close: "}}", Escape: "{{!}}"})
+	if replaced := replacer.replace("{{name}} {{!}}{{name}} name", "main.go"); replaced != "billing {{name}} name" {
+		t.Errorf("Unexpected replacement with a custom escape: %q", replaced)
+	}
+
+	if _, err := parseConfig([]byte("[delimiters]\nclose = \"__\"\n")); err == nil {
+		t.Errorf("Expected an error for delimiters without open")
+	}
+}
+
+func TestMatchModes(t *testing.T) {
+	tokens := []Token{
+		{Name: "Foo", Value: "Widget", Match: MatchIdentifier},
+		{Name: "foo", Value: "widget", Match: MatchWord},
+		{Name: "bar", Value: "baz"},
+		// A shorter token still matches where a longer one is not bounded
+		{Name: "FooBar", Value: "Gadget", Match: MatchWord},
+	}
+
+	replacer := newReplacer(tokens, Delimiters{})
+
+	tests := []struct {
+		subject  string
+		expected string
+	}{
+		{subject: "type Foo struct{}", expected: "type Widget struct{}"},
+		{subject: "FooBar myFoo Foo_test Foo2 (Foo)", expected: "Gadget myFoo Foo_test Foo2 (Widget)"},
+		{subject: "foo_test foo-bar football foo.go", expected: "widget_test widget-baz football widget.go"},
+		{subject: "xFoobar foobar", expected: "xFoobaz foobaz"},
+		{subject: "ünFoo Foo€", expected: "ünFoo Widget€"},
+	}
+
+	for _, test := range tests {
+		if replaced := replacer.replace(test.subject, "main.go"); replaced != test.expected {
+			t.Errorf("Unexpected replacement of %q. Expected %q, got %q", test.subject, test.expected, replaced)
+		}
+	}
+
+	if _, err := parseConfig([]byte("[[token]]\nname = \"foo\"\nmatch = \"regex\"\n")); err == nil {
+		t.Errorf("Expected an error for an unknown match mode")
+	}
+}
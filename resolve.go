@@ -0,0 +1,173 @@
+package scaffold
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CycleError reports token bindings that depend on themselves. Cycle starts
+// and ends with the same token name.
+type CycleError struct {
+	Cycle []string
+}
+
+func (err *CycleError) Error() string {
+	return "token binding cycle: " + strings.Join(err.Cycle, " -> ")
+}
+
+// resolveTokens returns a copy of the configured tokens with their final
+// values, leaving Config.Tokens untouched so Plan and Make can be repeated.
+func (scaf *Scaffold) resolveTokens() ([]Token, error) {
+	return scaf.resolveItems(nil)
+}
+
+// resolveItems resolves the tokens like resolveTokens, except that the tokens
+// named in items take a single item of a repeated list as their value.
+func (scaf *Scaffold) resolveItems(items map[string]string) ([]Token, error) {
+	tokens := slices.Clone(scaf.Config.Tokens)
+
+	order, err := bindingOrder(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]Token, len(tokens))
+
+	for _, i := range order {
+		token := &tokens[i]
+
+		input := token.Value
+		var parentItems []string
+
+		// A bound token takes the final value of the token it is bound to,
+		// and its type unless it declares one
+		if token.Token != "" {
+			parent := resolved[token.Token]
+			input = parent.Value
+
+			if token.Type == "" {
+				token.Type = parent.Type
+			}
+
+			if items, ok := parent.typed.([]string); ok {
+				parentItems = items
+			}
+		}
+
+		// If no value is set yet, try to get it from TokenValueMap (user-supplied values)
+		if input == "" {
+			input = scaf.TokenValueMap[token.Name]
+		}
+
+		if input == "" {
+			input = token.Default
+		}
+
+		if item, ok := items[token.Name]; ok {
+			input = item
+			parentItems = nil
+
+			if token.Type == TypeList {
+				token.Type = TypeString
+			}
+		}
+
+		if parentItems != nil && token.Type != TypeList {
+			parentItems = nil
+		}
+
+		scaf.setValue(token, input, parentItems)
+
+		if _, ok := resolved[token.Name]; !ok {
+			resolved[token.Name] = *token
+		}
+	}
+
+	return tokens, nil
+}
+
+func (scaf *Scaffold) applyModifiers(modifiers []string, value string) string {
+	for _, modifier := range modifiers {
+		for _, modFunc := range scaf.Modifiers[modifier] {
+			value = modFunc(value)
+		}
+	}
+
+	return value
+}
+
+// bindingOrder returns token indexes ordered so every token comes after the
+// token it is bound to, keeping config order otherwise. Bindings refer to the
+// first token declared with a name.
+func bindingOrder(tokens []Token) ([]int, error) {
+	indexes := make(map[string]int, len(tokens))
+	for i, token := range tokens {
+		if _, ok := indexes[token.Name]; !ok {
+			indexes[token.Name] = i
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(tokens))
+	order := make([]int, 0, len(tokens))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		token := tokens[i]
+		path = append(path, token.Name)
+
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			start := slices.Index(path, token.Name)
+			return &CycleError{Cycle: slices.Clone(path[start:])}
+		}
+
+		state[i] = visiting
+
+		if token.Token != "" {
+			parent, ok := indexes[token.Token]
+			if !ok {
+				return fmt.Errorf("token %q is bound to unknown token %q", token.Name, token.Token)
+			}
+
+			if err := visit(parent, path); err != nil {
+				return err
+			}
+		}
+
+		state[i] = visited
+		order = append(order, i)
+
+		return nil
+	}
+
+	for i := range tokens {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// tokenValues maps token names to their resolved, typed values, taking the
+// first token declared with a name.
+func tokenValues(tokens []Token) map[string]any {
+	values := make(map[string]any, len(tokens))
+
+	for _, token := range tokens {
+		if _, ok := values[token.Name]; !ok {
+			values[token.Name] = token.Typed()
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,107 @@
+package scaffold
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolveTokensDeepChain(t *testing.T) {
+	// Every token is declared before the token it is bound to
+	configContent := `
+		[[token]]
+		name = "e"
+		token = "d"
+		modifiers = ["upper"]
+
+		[[token]]
+		name = "d"
+		token = "c"
+
+		[[token]]
+		name = "c"
+		token = "b"
+		modifiers = ["snake"]
+
+		[[token]]
+		name = "b"
+		token = "a"
+
+		[[token]]
+		name = "a"
+		modifiers = ["pascal"]
+	`
+	scaf, err := InitFS(fstest.MapFS{"scaffold.toml": &fstest.MapFile{Data: []byte(configContent)}}, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.RegisterTokenValue("a", "my-service")
+
+	tokens, err := scaf.resolveTokens()
+	if err != nil {
+		t.Fatalf("Failed to resolve tokens: %v", err)
+	}
+
+	expected := map[string]string{
+		"a": "MyService",
+		"b": "MyService",
+		"c": "my_service",
+		"d": "my_service",
+		"e": "MY_SERVICE",
+	}
+
+	for _, token := range tokens {
+		if token.Value != expected[token.Name] {
+			t.Errorf("Unexpected value for %s. Expected %q, got %q", token.Name, expected[token.Name], token.Value)
+		}
+	}
+}
+
+func TestResolveTokensCycle(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "a"
+		token = "b"
+
+		[[token]]
+		name = "b"
+		token = "c"
+
+		[[token]]
+		name = "c"
+		token = "a"
+	`
+	scaf, err := InitFS(fstest.MapFS{"scaffold.toml": &fstest.MapFile{Data: []byte(configContent)}}, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	_, err = scaf.Plan(t.TempDir())
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a cycle error, got %v", err)
+	}
+
+	expected := "token binding cycle: a -> b -> c -> a"
+	if err.Error() != expected {
+		t.Errorf("Unexpected error message. Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestResolveTokensUnknownBinding(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "a"
+		token = "missing"
+	`
+	scaf, err := InitFS(fstest.MapFS{"scaffold.toml": &fstest.MapFile{Data: []byte(configContent)}}, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	if _, err := scaf.resolveTokens(); err == nil {
+		t.Errorf("Expected an error for a binding to an unknown token")
+	}
+}
@@ -1,12 +1,9 @@
 package scaffold
 
 import (
-	"cmp"
 	"errors"
+	"io/fs"
 	"os"
-	"path/filepath"
-	"slices"
-	"strings"
 )
 
 const (
@@ -15,23 +12,52 @@ const (
 
 type Scaffold struct {
 	Path          string
+	FS            fs.FS
 	Config        Config
 	Modifiers     modifierMap
 	TokenValueMap map[string]string
-	onMakeFunc    func(string)
+	// AnswersFile names the file Make records input values in, relative to
+	// the destination. Set it to an empty string to disable it.
+	AnswersFile string
+	onMakeFunc  func(string)
+
+	conflictPolicy ConflictPolicy
+	onConflictFunc func(string) ConflictPolicy
 }
 
 func Init(templatesPath string) (*Scaffold, error) {
-	config, err := getConfig(templatesPath + "/" + configFileName)
+	scaffold, err := InitFS(os.DirFS(templatesPath), ".")
+	if err != nil {
+		return nil, err
+	}
+
+	scaffold.Path = templatesPath
+
+	return scaffold, nil
+}
+
+// InitFS loads a template rooted at root within fsys, which lets templates
+// come from an embed.FS, a zip reader or an fstest.MapFS as well as disk.
+func InitFS(fsys fs.FS, root string) (*Scaffold, error) {
+	templateFS, err := fs.Sub(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := getConfigFS(templateFS, configFileName)
 	if err != nil {
 		return nil, err
 	}
 
 	scaffold := &Scaffold{
-		Path:          templatesPath,
+		Path:          root,
+		FS:            templateFS,
 		Config:        config,
 		Modifiers:     make(modifierMap),
 		TokenValueMap: make(map[string]string),
+		AnswersFile:   answersFileName,
+
+		conflictPolicy: ConflictOverwrite,
 	}
 
 	scaffold.onMakeFunc = func(_ string) {}
@@ -65,125 +91,92 @@ func (scaf *Scaffold) GetTokens() []Token {
 	return scaf.Config.Tokens
 }
 
-func (scaf *Scaffold) OnMake(onMakeFunc func(string)) {
-	scaf.onMakeFunc = onMakeFunc
-}
-
-func (scaf *Scaffold) Make(destination string) error {
-	// First pass: Set all token values
-	for i := range scaf.Config.Tokens {
-		token := &scaf.Config.Tokens[i]
-
-		// If token depends on another token, get its value
-		if token.Token != "" {
-			parentToken, err := scaf.GetTokenByName(token.Token)
-			if err == nil {
-				token.Value = parentToken.Value
-			}
-		}
+// Inputs returns the tokens a user has to supply values for, in config order
+// and once per name.
+func (scaf *Scaffold) Inputs() []Token {
+	var inputs []Token
 
-		// If no value is set yet, try to get it from TokenValueMap (user-supplied values)
-		if token.Value == "" {
-			token.Value = scaf.TokenValueMap[token.Name]
+	for _, token := range scaf.Config.Tokens {
+		if !token.IsInput() {
+			continue
 		}
 
-		// Apply modifiers
-		for _, modifier := range token.Modifiers {
-			for _, modFunc := range scaf.Modifiers[modifier] {
-				token.Value = modFunc(token.Value)
-			}
+		if _, ok := findToken(inputs, token.Name); ok {
+			continue
 		}
-	}
 
-	// Second pass: Process any remaining token dependencies
-	for i := range scaf.Config.Tokens {
-		token := &scaf.Config.Tokens[i]
-		if token.Token != "" && token.Value == "" {
-			parentToken, err := scaf.GetTokenByName(token.Token)
-			if err == nil && parentToken.Value != "" {
-				token.Value = parentToken.Value
-				// Apply modifiers again for newly set values
-				for _, modifier := range token.Modifiers {
-					for _, modFunc := range scaf.Modifiers[modifier] {
-						token.Value = modFunc(token.Value)
-					}
-				}
-			}
-		}
+		inputs = append(inputs, token)
 	}
 
-	// Sort tokens by priority for replacement order
-	slices.SortFunc(scaf.Config.Tokens, func(a, b Token) int {
-		return cmp.Compare(b.Priority, a.Priority)
-	})
-
-	if err := filepath.WalkDir(scaf.Path, func(path string, info os.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
+	return inputs
+}
 
-		if info.Name() == configFileName {
-			return nil
-		}
+func (scaf *Scaffold) OnMake(onMakeFunc func(string)) {
+	scaf.onMakeFunc = onMakeFunc
+}
 
-		if path == scaf.Path {
-			return nil
-		}
+// Make generates the template into destination. Every change is journaled,
+// and if any step fails the destination is restored to its previous state.
+func (scaf *Scaffold) Make(destination string) error {
+	return scaf.make(NewDirOutput(destination), destination)
+}
 
-		relativePath := strings.TrimPrefix(path, scaf.Path)
+// MakeTo generates the template into out. Failed runs are only rolled back
+// when out is an EditableOutput.
+func (scaf *Scaffold) MakeTo(out Output) error {
+	return scaf.make(out, "")
+}
 
-		relativePath = scaf.replaceTokens(relativePath, path)
+func (scaf *Scaffold) make(out Output, root string) error {
+	operations, err := scaf.plan(out, root, true, false)
+	if err != nil {
+		return err
+	}
 
-		makeDestination := destination + relativePath
+	tx := &transaction{out: out}
 
-		if info.IsDir() {
-			return os.MkdirAll(makeDestination, os.ModePerm)
-		}
+	for _, operation := range operations {
+		if err := scaf.apply(tx, operation); err != nil {
+			if rollbackErr := tx.rollback(); rollbackErr != nil {
+				return errors.Join(err, rollbackErr)
+			}
 
-		contents, err := os.ReadFile(path)
-		if err != nil {
 			return err
 		}
+	}
 
-		stringcontents := string(contents)
-		stringcontents = scaf.replaceTokens(stringcontents, path)
+	return nil
+}
 
-		if err := os.WriteFile(makeDestination, []byte(stringcontents), 0644); err != nil {
+func (scaf *Scaffold) apply(tx *transaction, operation Operation) error {
+	switch operation.Type {
+	case OpMkdir:
+		return tx.mkdirAll(operation.name, operation.perm)
+	case OpCreate, OpOverwrite, OpInject:
+		if err := tx.writeFile(operation.name, operation.contents, operation.perm); err != nil {
 			return err
 		}
 
-		scaf.onMakeFunc(makeDestination)
-
-		return nil
-	}); err != nil {
-		return err
+		scaf.onMakeFunc(operation.Path)
 	}
 
 	return nil
 }
 
-func (scaf *Scaffold) replaceTokens(subject string, path string) string {
-	for _, token := range scaf.Config.Tokens {
-		if token.Localize != nil {
-			for _, tokenPath := range token.Localize {
-				if strings.HasPrefix(path, scaf.Path+"/"+tokenPath) {
-					subject = strings.ReplaceAll(subject, token.Name, token.Value)
-				}
-			}
-		} else {
-			subject = strings.ReplaceAll(subject, token.Name, token.Value)
-		}
+func (scaf *Scaffold) GetTokenByName(name string) (Token, error) {
+	if token, ok := findToken(scaf.Config.Tokens, name); ok {
+		return token, nil
 	}
 
-	return subject
+	return Token{}, errors.New("token not found")
 }
 
-func (scaf *Scaffold) GetTokenByName(name string) (Token, error) {
-	for _, token := range scaf.Config.Tokens {
+func findToken(tokens []Token, name string) (Token, bool) {
+	for _, token := range tokens {
 		if token.Name == name {
-			return token, nil
+			return token, true
 		}
 	}
 
-	return Token{}, errors.New("token not found")
+	return Token{}, false
 }
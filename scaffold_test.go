@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 )
 
 func TestMake(t *testing.T) {
@@ -407,3 +408,136 @@ func ppaymFunc() {
 		t.Errorf("Generated content does not match expected content.\nExpected:\n%s\nGot:\n%s", expectedContent, string(generatedContent))
 	}
 }
+
+// createTemplate writes a template directory containing scaffold.toml and the
+// given files into a new temporary directory and returns the template path.
+func createTemplate(t *testing.T, configContent string, files map[string]string) string {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "scaffold-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	templateDir := filepath.Join(tmpDir, "template")
+	err = os.MkdirAll(templateDir, 0755)
+	if err != nil {
+		t.Fatalf("Failed to create template dir: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(templateDir, "scaffold.toml"), []byte(configContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	for name, content := range files {
+		filePath := filepath.Join(templateDir, filepath.FromSlash(name))
+
+		err = os.MkdirAll(filepath.Dir(filePath), 0755)
+		if err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", name, err)
+		}
+
+		err = os.WriteFile(filePath, []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	return templateDir
+}
+
+func TestInitFS(t *testing.T) {
+	templates := fstest.MapFS{
+		"templates/service/scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "{{name}}"
+
+			[[token]]
+			name = "{{Name}}"
+			token = "{{name}}"
+			modifiers = ["pascal"]
+		`)},
+		"templates/service/{{name}}/main.go": &fstest.MapFile{Data: []byte("package {{name}}\n\ntype {{Name}} struct{}\n")},
+		"templates/other/ignored.txt":        &fstest.MapFile{Data: []byte("not part of the template")},
+	}
+
+	scaf, err := InitFS(templates, "templates/service")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.RegisterTokenValue("{{name}}", "billing")
+
+	destDir := t.TempDir()
+
+	err = scaf.Make(destDir)
+	if err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	generatedContent, err := os.ReadFile(filepath.Join(destDir, "billing", "main.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	expectedContent := "package billing\n\ntype Billing struct{}\n"
+
+	if string(generatedContent) != expectedContent {
+		t.Errorf("Generated content does not match expected content.\nExpected:\n%s\nGot:\n%s", expectedContent, string(generatedContent))
+	}
+
+	if _, err := os.Stat(filepath.Join(destDir, "scaffold.toml")); !os.IsNotExist(err) {
+		t.Errorf("scaffold.toml should not be copied to the destination")
+	}
+}
+
+func TestInputs(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "service"
+		description = "Name of the service, used for the module and binary"
+		prompt = "Service name"
+		example = "billing-api"
+		group = "General"
+
+		[[token]]
+		name = "Service"
+		token = "service"
+		modifiers = ["pascal"]
+
+		[[token]]
+		name = "version"
+		value = "1.0.0"
+
+		[[token]]
+		name = "db"
+		default = "postgres"
+		group = "Storage"
+
+		[[token]]
+		name = "service"
+		localize = ["cmd"]
+	`
+	templateDir := createTemplate(t, configContent, nil)
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	inputs := scaf.Inputs()
+
+	if len(inputs) != 2 || inputs[0].Name != "service" || inputs[1].Name != "db" {
+		t.Fatalf("Unexpected inputs: %+v", inputs)
+	}
+
+	service := inputs[0]
+	if service.Description != "Name of the service, used for the module and binary" ||
+		service.Prompt != "Service name" ||
+		service.Example != "billing-api" ||
+		service.Group != "General" {
+		t.Errorf("Token metadata was not loaded: %+v", service)
+	}
+}
@@ -0,0 +1,178 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// transaction journals every change Make applies to the output so a failed
+// run can be undone instead of leaving a half generated tree behind. Changes
+// are only journaled when the output is an EditableOutput.
+type transaction struct {
+	out     Output
+	created []string
+	backups []backup
+}
+
+type backup struct {
+	name     string
+	contents []byte
+	mode     fs.FileMode
+}
+
+// modeOutput is an Output that can report the permissions of its files, so
+// a rollback can restore them.
+type modeOutput interface {
+	Mode(name string) (fs.FileMode, error)
+}
+
+func (tx *transaction) mkdirAll(name string, perm fs.FileMode) error {
+	editable, ok := tx.out.(EditableOutput)
+	if !ok {
+		return tx.out.MkdirAll(name, perm)
+	}
+
+	// Record every missing ancestor, since MkdirAll creates all of them
+	var missing []string
+	for dir := path.Clean(name); ; {
+		exists, err := editable.Exists(dir)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			break
+		}
+
+		missing = append([]string{dir}, missing...)
+
+		parent, ok := tx.parent(dir)
+		if !ok {
+			break
+		}
+
+		dir = parent
+	}
+
+	if err := editable.MkdirAll(name, perm); err != nil {
+		return err
+	}
+
+	tx.created = append(tx.created, missing...)
+
+	return nil
+}
+
+// parent returns the directory containing dir. Above the root of the output
+// only a directory on disk has parents, named "..", "../.." and so on.
+func (tx *transaction) parent(dir string) (string, bool) {
+	if dir != "." && dir != ".." && !strings.HasPrefix(dir, "../") {
+		return path.Dir(dir), true
+	}
+
+	out, ok := tx.out.(*DirOutput)
+	if !ok {
+		return "", false
+	}
+
+	current, err := filepath.Abs(out.path(dir))
+	if err != nil || filepath.Dir(current) == current {
+		return "", false
+	}
+
+	return path.Join(dir, ".."), true
+}
+
+// mode returns the permissions of an existing file, or the default ones when
+// the output cannot report them.
+func (tx *transaction) mode(name string) (fs.FileMode, error) {
+	out, ok := tx.out.(modeOutput)
+	if !ok {
+		return defaultFilePerm, nil
+	}
+
+	return out.Mode(name)
+}
+
+func (tx *transaction) writeFile(name string, contents []byte, perm fs.FileMode) error {
+	editable, ok := tx.out.(EditableOutput)
+	if !ok {
+		return tx.out.WriteFile(name, contents, perm)
+	}
+
+	exists, err := editable.Exists(name)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		original, err := editable.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		mode, err := tx.mode(name)
+		if err != nil {
+			return err
+		}
+
+		tx.backups = append(tx.backups, backup{name: name, contents: original, mode: mode})
+	} else {
+		tx.created = append(tx.created, name)
+	}
+
+	return editable.WriteFile(name, contents, perm)
+}
+
+func (tx *transaction) remove(name string) error {
+	editable, ok := tx.out.(EditableOutput)
+	if !ok {
+		return errors.New("output does not support removing files")
+	}
+
+	original, err := editable.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	mode, err := tx.mode(name)
+	if err != nil {
+		return err
+	}
+
+	tx.backups = append(tx.backups, backup{name: name, contents: original, mode: mode})
+
+	return editable.Remove(name)
+}
+
+// rollback restores overwritten files and removes everything created, newest
+// first, returning every error it could not recover from.
+func (tx *transaction) rollback() error {
+	editable, ok := tx.out.(EditableOutput)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+
+	for i := len(tx.backups) - 1; i >= 0; i-- {
+		backup := tx.backups[i]
+		if err := editable.WriteFile(backup.name, backup.contents, backup.mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i := len(tx.created) - 1; i >= 0; i-- {
+		if err := editable.Remove(tx.created[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	tx.created = nil
+	tx.backups = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakeRollsBackOnFailure(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+		value = "generated"
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"a.txt":     "{{name}}",
+		"m.txt":     "{{name}}",
+		"sub/x.txt": "{{name}}",
+		"z.txt":     "{{name}}",
+	})
+
+	destDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(destDir, "m.txt"), []byte("edited"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write existing file: %v", err)
+	}
+
+	// A directory where the template has a file makes the last write fail
+	err = os.MkdirAll(filepath.Join(destDir, "z.txt"), 0755)
+	if err != nil {
+		t.Fatalf("Failed to create blocking dir: %v", err)
+	}
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	if err := scaf.Make(destDir); err == nil {
+		t.Fatalf("Expected Make to fail")
+	}
+
+	for _, name := range []string{"a.txt", "sub"} {
+		if _, err := os.Stat(filepath.Join(destDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed by the rollback", name)
+		}
+	}
+
+	restored, err := os.ReadFile(filepath.Join(destDir, "m.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read restored file: %v", err)
+	}
+	if string(restored) != "edited" {
+		t.Errorf("Expected m.txt to be restored, got %q", string(restored))
+	}
+
+	info, err := os.Stat(filepath.Join(destDir, "z.txt"))
+	if err != nil || !info.IsDir() {
+		t.Errorf("Expected pre-existing z.txt directory to be left alone")
+	}
+}
+
+func TestMakeCreatesMissingDestination(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+		value = "generated"
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"a.txt": "{{name}}",
+	})
+
+	destDir := filepath.Join(t.TempDir(), "nested", "output")
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	if err := scaf.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	generated, err := os.ReadFile(filepath.Join(destDir, "a.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if string(generated) != "generated" {
+		t.Errorf("Unexpected generated content: %q", string(generated))
+	}
+}
+
+func TestRollbackRemovesMissingParents(t *testing.T) {
+	baseDir := t.TempDir()
+	destDir := filepath.Join(baseDir, "x", "y", "dest")
+
+	tx := &transaction{out: NewDirOutput(destDir)}
+
+	if err := tx.mkdirAll(".", 0755); err != nil {
+		t.Fatalf("Failed to create destination: %v", err)
+	}
+
+	if err := tx.writeFile("a.txt", []byte("generated"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := tx.rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(baseDir, "x")); !os.IsNotExist(err) {
+		t.Errorf("Expected the missing parents of the destination to be removed")
+	}
+
+	if _, err := os.Stat(baseDir); err != nil {
+		t.Errorf("Expected the existing parent to be left alone")
+	}
+}
+
+func TestRollbackRestoresMode(t *testing.T) {
+	out := NewMemoryOutput()
+	out.WriteFile("run.sh", []byte("original"), 0755)
+
+	tx := &transaction{out: out}
+
+	if err := tx.remove("run.sh"); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+
+	if err := tx.rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+
+	file := out.Files["run.sh"]
+	if file == nil || string(file.Data) != "original" || file.Mode != 0755 {
+		t.Errorf("Expected run.sh to be restored with its mode, got %+v", file)
+	}
+}
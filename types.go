@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	TypeString = "string"
+	TypeBool   = "bool"
+	TypeInt    = "int"
+	TypeList   = "list"
+)
+
+const (
+	defaultListSeparator = ", "
+)
+
+func validType(tokenType string) bool {
+	switch tokenType {
+	case "", TypeString, TypeBool, TypeInt, TypeList:
+		return true
+	}
+
+	return false
+}
+
+// splitList splits a comma separated list value into trimmed, non-empty items.
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Typed returns the resolved value of the token as a bool, int or []string
+// according to its type, or as a string. Values that do not parse as their
+// type are returned as strings.
+func (token Token) Typed() any {
+	if token.typed != nil {
+		return token.typed
+	}
+
+	return token.Value
+}
+
+// setValue stores the resolved value of the token from its input, applying
+// modifiers to the string form of the value, or to each item of a list.
+// parentItems are the items of the token it is bound to, if that is a list.
+func (scaf *Scaffold) setValue(token *Token, input string, parentItems []string) {
+	token.input = input
+	token.typed = nil
+
+	switch token.Type {
+	case TypeList:
+		items := parentItems
+		if items == nil {
+			items = splitList(input)
+		}
+
+		items = slices.Clone(items)
+		for i := range items {
+			items[i] = scaf.applyModifiers(token.Modifiers, items[i])
+		}
+
+		token.typed = items
+		token.Value = strings.Join(items, token.separator())
+
+		return
+	case TypeBool:
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(input)); err == nil {
+			token.typed = parsed
+			input = strconv.FormatBool(parsed)
+		}
+	case TypeInt:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(input)); err == nil {
+			token.typed = parsed
+			input = strconv.Itoa(parsed)
+		}
+	}
+
+	token.Value = scaf.applyModifiers(token.Modifiers, input)
+}
+
+func (token Token) separator() string {
+	if token.Separator == "" {
+		return defaultListSeparator
+	}
+
+	return token.Separator
+}
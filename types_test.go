@@ -0,0 +1,126 @@
+package scaffold
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTypedTokens(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "entities"
+			type = "list"
+			modifiers = ["pascal"]
+
+			[[token]]
+			name = "tables"
+			token = "entities"
+			modifiers = ["snake", "plural"]
+			separator = " "
+
+			[[token]]
+			name = "replicas"
+			type = "int"
+
+			[[token]]
+			name = "metrics"
+			type = "bool"
+			default = "no"
+
+			[[include]]
+			path = "deploy"
+			when = "replicas > 1"
+
+			[[include]]
+			path = "metrics.go"
+			when = "metrics"
+		`)},
+		"values.txt":      &fstest.MapFile{Data: []byte("entities\ntables\nreplicas\n")},
+		"deploy/hpa.yaml": &fstest.MapFile{Data: []byte("replicas: replicas")},
+		"metrics.go":      &fstest.MapFile{Data: []byte("package metrics")},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+
+	// The default for a bool must itself be a valid bool
+	scaf.RegisterTokenValue("entities", "user, order_item,invoice")
+	scaf.RegisterTokenValue("replicas", " 03")
+
+	err = scaf.Validate()
+	var tokenErr *TokenError
+	if !errors.As(err, &tokenErr) || tokenErr.Token != "metrics" || !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Expected the metrics default to be rejected, got %v", err)
+	}
+
+	if err := scaf.SetTokenValue("metrics", "maybe"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected an invalid bool to be rejected, got %v", err)
+	}
+
+	if err := scaf.SetTokenValue("replicas", "three"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected an invalid int to be rejected, got %v", err)
+	}
+
+	// Like ints, bools are trimmed
+	if err := scaf.SetTokenValue("metrics", " false "); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+
+	tokens, err := scaf.resolveTokens()
+	if err != nil {
+		t.Fatalf("Failed to resolve tokens: %v", err)
+	}
+
+	entities, _ := findToken(tokens, "entities")
+	if !slices.Equal(entities.Typed().([]string), []string{"User", "OrderItem", "Invoice"}) {
+		t.Errorf("Unexpected entities items: %v", entities.Typed())
+	}
+
+	tables, _ := findToken(tokens, "tables")
+	if tables.Type != TypeList || tables.Value != "users order_items invoices" {
+		t.Errorf("Unexpected tables value: %q", tables.Value)
+	}
+
+	replicas, _ := findToken(tokens, "replicas")
+	if replicas.Typed() != 3 || replicas.Value != "3" {
+		t.Errorf("Unexpected replicas value: %v", replicas.Typed())
+	}
+
+	metrics, _ := findToken(tokens, "metrics")
+	if metrics.Typed() != false || metrics.Value != "false" {
+		t.Errorf("Unexpected metrics value: %v", metrics.Typed())
+	}
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected := "User, OrderItem, Invoice\nusers order_items invoices\n3\n"
+	if string(out.Files["values.txt"].Data) != expected {
+		t.Errorf("Unexpected content.\nExpected:\n%s\nGot:\n%s", expected, out.Files["values.txt"].Data)
+	}
+
+	if out.Files["deploy/hpa.yaml"] == nil || out.Files["metrics.go"] != nil {
+		t.Errorf("Unexpected generated files: %v", out.Files)
+	}
+}
+
+func TestUnknownTokenType(t *testing.T) {
+	_, err := parseConfig([]byte(`
+		[[token]]
+		name = "replicas"
+		type = "float"
+	`))
+	if err == nil {
+		t.Errorf("Expected an error for an unknown type")
+	}
+}
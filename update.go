@@ -0,0 +1,203 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// UpdateReport lists the destination files Update changed. Files in
+// Conflicts contain conflict markers and need to be resolved by hand.
+type UpdateReport struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Merged    []string `json:"merged"`
+	Removed   []string `json:"removed"`
+	Conflicts []string `json:"conflicts"`
+}
+
+// Update brings a project generated from previous up to date with this
+// template. Both templates are rendered with the values recorded in the
+// destination's answers file, and the template changes are merged three ways
+// into the destination, keeping local modifications. Values registered on
+// this Scaffold take precedence over recorded ones.
+func (scaf *Scaffold) Update(previous *Scaffold, destination string) (UpdateReport, error) {
+	report := UpdateReport{}
+	out := NewDirOutput(destination)
+
+	if scaf.AnswersFile != "" {
+		answers, err := ReadAnswers(filepath.Join(destination, filepath.FromSlash(scaf.AnswersFile)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return report, err
+		}
+
+		for name, value := range answers.Values {
+			if _, ok := scaf.TokenValueMap[name]; !ok {
+				scaf.RegisterTokenValue(name, value)
+			}
+		}
+	}
+
+	for name, value := range scaf.TokenValueMap {
+		if _, ok := previous.TokenValueMap[name]; !ok {
+			previous.RegisterTokenValue(name, value)
+		}
+	}
+
+	baseFiles, basePerms, err := previous.render()
+	if err != nil {
+		return report, err
+	}
+
+	theirFiles, perms, err := scaf.render()
+	if err != nil {
+		return report, err
+	}
+
+	names := slices.Sorted(maps.Keys(theirFiles))
+	for name := range baseFiles {
+		if _, ok := theirFiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	tx := &transaction{out: out}
+
+	for _, name := range names {
+		if err := scaf.updateFile(tx, &report, destination, name, baseFiles, theirFiles, basePerms, perms); err != nil {
+			if rollbackErr := tx.rollback(); rollbackErr != nil {
+				return UpdateReport{}, errors.Join(err, rollbackErr)
+			}
+
+			return UpdateReport{}, err
+		}
+	}
+
+	return report, nil
+}
+
+func (scaf *Scaffold) updateFile(tx *transaction, report *UpdateReport, destination string, name string, baseFiles map[string][]byte, theirFiles map[string][]byte, basePerms map[string]fs.FileMode, perms map[string]fs.FileMode) error {
+	out := tx.out.(EditableOutput)
+	displayPath := outputPath(destination, name)
+
+	base, inBase := baseFiles[name]
+	theirs, inTheirs := theirFiles[name]
+
+	ours, err := out.ReadFile(name)
+	inOurs := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// perm returns the permissions the template generates a path with
+	perm := func(name string, defaultPerm fs.FileMode) fs.FileMode {
+		if perm, ok := perms[name]; ok {
+			return perm
+		}
+
+		return defaultPerm
+	}
+
+	write := func(contents []byte) error {
+		var dirs []string
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			dirs = append([]string{dir}, dirs...)
+		}
+
+		for _, dir := range dirs {
+			if err := tx.mkdirAll(dir, perm(dir, defaultDirPerm)); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.writeFile(name, contents, perm(name, defaultFilePerm)); err != nil {
+			return err
+		}
+
+		scaf.onMakeFunc(displayPath)
+
+		return nil
+	}
+
+	switch {
+	// The answers file always records the latest template and values
+	case name == scaf.AnswersFile:
+		if inOurs && string(ours) == string(theirs) {
+			return nil
+		}
+
+		return write(theirs)
+
+	// Nothing changed in the template, or both sides made the same change,
+	// though the template may still have changed the permissions
+	case inBase == inTheirs && string(base) == string(theirs),
+		inOurs == inTheirs && string(ours) == string(theirs):
+		if !inOurs || !inTheirs || basePerms[name] == perms[name] {
+			return nil
+		}
+
+		report.Updated = append(report.Updated, displayPath)
+		return write(ours)
+
+	// A file new to the template
+	case !inOurs && !inBase:
+		report.Created = append(report.Created, displayPath)
+		return write(theirs)
+
+	// Removed locally, so local changes win
+	case !inOurs:
+		return nil
+
+	// Unmodified since it was generated, so take the template as is
+	case inBase && string(ours) == string(base):
+		if !inTheirs {
+			report.Removed = append(report.Removed, displayPath)
+			return tx.remove(name)
+		}
+
+		report.Updated = append(report.Updated, displayPath)
+		return write(theirs)
+
+	// Removed from the template but modified locally
+	case !inTheirs:
+		report.Conflicts = append(report.Conflicts, displayPath)
+		return nil
+	}
+
+	merged, conflicted := merge3(string(base), string(ours), string(theirs))
+
+	if conflicted {
+		report.Conflicts = append(report.Conflicts, displayPath)
+	} else {
+		report.Merged = append(report.Merged, displayPath)
+	}
+
+	return write([]byte(merged))
+}
+
+// render generates the template in memory and returns every file by name,
+// along with the permissions of every file and directory.
+func (scaf *Scaffold) render() (map[string][]byte, map[string]fs.FileMode, error) {
+	operations, err := scaf.plan(NewMemoryOutput(), "", false, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := make(map[string][]byte)
+	perms := make(map[string]fs.FileMode)
+
+	for _, operation := range operations {
+		switch operation.Type {
+		case OpCreate, OpOverwrite:
+			files[operation.name] = operation.contents
+			perms[operation.name] = operation.perm
+		case OpMkdir:
+			perms[operation.name] = operation.perm
+		}
+	}
+
+	return files, perms, nil
+}
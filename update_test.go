@@ -0,0 +1,200 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	configV1 := `
+		version = "1.0.0"
+
+		[[token]]
+		name = "{{name}}"
+	`
+	configV2 := `
+		version = "2.0.0"
+
+		[[token]]
+		name = "{{name}}"
+	`
+
+	v1 := createTemplate(t, configV1, map[string]string{
+		"main.go":      "package main\n\nfunc main() {\n\tprintln(\"{{name}}\")\n}\n",
+		"config.yaml":  "name: {{name}}\nport: 8080\n",
+		"untouched.md": "# {{name}}\n",
+		"obsolete.txt": "{{name}}\n",
+		"notes.txt":    "{{name}}\n",
+	})
+	v2 := createTemplate(t, configV2, map[string]string{
+		"main.go":      "// {{name}} service\npackage main\n\nfunc main() {\n\tprintln(\"{{name}}\")\n}\n",
+		"config.yaml":  "name: {{name}}\nport: 9090\n",
+		"untouched.md": "# {{name}}\n\nGenerated by scaffold.\n",
+		"new/file.txt": "{{name}}\n",
+	})
+
+	previous, err := Init(v1)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	previous.RegisterTokenValue("{{name}}", "billing")
+
+	destDir := t.TempDir()
+
+	if err := previous.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	// Local modifications to the generated project
+	localChanges := map[string]string{
+		"main.go":     "package main\n\nfunc main() {\n\tprintln(\"billing\")\n\tserve()\n}\n",
+		"config.yaml": "name: billing\nport: 7070\n",
+		"notes.txt":   "billing\nlocal notes\n",
+	}
+
+	for name, content := range localChanges {
+		err := os.WriteFile(filepath.Join(destDir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to modify %s: %v", name, err)
+		}
+	}
+
+	// Values come from the recorded answers file
+	scaf, err := Init(v2)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	report, err := scaf.Update(previous, destDir)
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	expectedReport := UpdateReport{
+		Created:   []string{filepath.Join(destDir, "new/file.txt")},
+		Updated:   []string{filepath.Join(destDir, "untouched.md")},
+		Merged:    []string{filepath.Join(destDir, "main.go")},
+		Removed:   []string{filepath.Join(destDir, "obsolete.txt")},
+		Conflicts: []string{filepath.Join(destDir, "config.yaml"), filepath.Join(destDir, "notes.txt")},
+	}
+
+	for _, check := range []struct {
+		name     string
+		expected []string
+		actual   []string
+	}{
+		{"created", expectedReport.Created, report.Created},
+		{"updated", expectedReport.Updated, report.Updated},
+		{"merged", expectedReport.Merged, report.Merged},
+		{"removed", expectedReport.Removed, report.Removed},
+		{"conflicts", expectedReport.Conflicts, report.Conflicts},
+	} {
+		slices.Sort(check.actual)
+		if !slices.Equal(check.expected, check.actual) {
+			t.Errorf("Unexpected %s files. Expected %v, got %v", check.name, check.expected, check.actual)
+		}
+	}
+
+	expectedFiles := map[string]string{
+		"main.go":      "// billing service\npackage main\n\nfunc main() {\n\tprintln(\"billing\")\n\tserve()\n}\n",
+		"config.yaml":  "name: billing\n<<<<<<< current\nport: 7070\n=======\nport: 9090\n>>>>>>> template\n",
+		"untouched.md": "# billing\n\nGenerated by scaffold.\n",
+		"new/file.txt": "billing\n",
+		"notes.txt":    "billing\nlocal notes\n",
+	}
+
+	for name, content := range expectedFiles {
+		actual, err := os.ReadFile(filepath.Join(destDir, name))
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", name, err)
+			continue
+		}
+
+		if string(actual) != content {
+			t.Errorf("Unexpected content for %s.\nExpected:\n%s\nGot:\n%s", name, content, string(actual))
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(destDir, "obsolete.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected obsolete.txt to be removed")
+	}
+
+	answers, err := ReadAnswers(filepath.Join(destDir, answersFileName))
+	if err != nil {
+		t.Fatalf("Failed to read answers: %v", err)
+	}
+
+	if answers.Version != "2.0.0" || answers.Values["{{name}}"] != "billing" {
+		t.Errorf("Expected answers to record the new template, got %+v", answers)
+	}
+}
+
+func TestUpdatePermissions(t *testing.T) {
+	config := `
+		[[token]]
+		name = "{{name}}"
+	`
+
+	v1 := createTemplate(t, config, map[string]string{
+		"main.go":           "package {{name}}\n",
+		"scripts/build.sh":  "#!/bin/sh\n",
+		"scripts/deploy.sh": "#!/bin/sh\n",
+	})
+	v2 := createTemplate(t, config, map[string]string{
+		"main.go":           "package {{name}}\n",
+		"scripts/build.sh":  "#!/bin/sh\n",
+		"scripts/deploy.sh": "#!/bin/sh\ndeploy\n",
+		"scripts/run.sh":    "#!/bin/sh\n",
+	})
+
+	// New, updated and otherwise unchanged files all become executable
+	scripts := []string{"build.sh", "deploy.sh", "run.sh"}
+
+	for _, script := range scripts {
+		if err := os.Chmod(filepath.Join(v2, "scripts", script), 0755); err != nil {
+			t.Fatalf("Failed to chmod template file: %v", err)
+		}
+	}
+
+	previous, err := Init(v1)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	previous.RegisterTokenValue("{{name}}", "billing")
+
+	destDir := t.TempDir()
+
+	if err := previous.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	current, err := Init(v2)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	report, err := current.Update(previous, destDir)
+	if err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	for _, script := range scripts {
+		info, err := os.Stat(filepath.Join(destDir, "scripts", script))
+		if err != nil {
+			t.Fatalf("Failed to stat updated file: %v", err)
+		}
+
+		if info.Mode().Perm()&0100 == 0 {
+			t.Errorf("Expected %s to be executable, got %v", script, info.Mode())
+		}
+	}
+
+	updated := []string{filepath.Join(destDir, "scripts", "build.sh"), filepath.Join(destDir, "scripts", "deploy.sh")}
+	if !slices.Equal(report.Updated, updated) {
+		t.Errorf("Expected %v to be updated, got %v", updated, report.Updated)
+	}
+}
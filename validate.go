@@ -0,0 +1,148 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrMissingValue = errors.New("missing value")
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// TokenError describes a problem with the value of a single token.
+type TokenError struct {
+	Token string
+	Err   error
+}
+
+func (err *TokenError) Error() string {
+	return fmt.Sprintf("token %q: %v", err.Token, err.Err)
+}
+
+func (err *TokenError) Unwrap() error {
+	return err.Err
+}
+
+// Validate checks every token before anything is generated and reports all
+// problems at once, joined into a single error of TokenErrors.
+func (scaf *Scaffold) Validate() error {
+	tokens, err := scaf.resolveTokens()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var reported []string
+
+	for i, token := range scaf.Config.Tokens {
+		if slices.Contains(reported, token.Name) {
+			continue
+		}
+
+		if token.IsRequired() && tokens[i].Value == "" {
+			errs = append(errs, &TokenError{Token: token.Name, Err: ErrMissingValue})
+			reported = append(reported, token.Name)
+			continue
+		}
+
+		if tokens[i].input == "" {
+			continue
+		}
+
+		if err := tokens[i].CheckValue(tokens[i].input); err != nil {
+			errs = append(errs, err)
+			reported = append(reported, token.Name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetTokenValue registers a value like RegisterTokenValue, but rejects values
+// that break the rules declared for the token.
+func (scaf *Scaffold) SetTokenValue(tokenName string, value string) error {
+	token, err := scaf.GetTokenByName(tokenName)
+	if err != nil {
+		return &TokenError{Token: tokenName, Err: err}
+	}
+
+	if err := token.CheckValue(value); err != nil {
+		return err
+	}
+
+	scaf.RegisterTokenValue(tokenName, value)
+
+	return nil
+}
+
+// CheckValue checks a value, before modifiers are applied, against the type
+// and the pattern, length and choices rules of the token. The rules apply to
+// every item of a list.
+func (token Token) CheckValue(value string) error {
+	switch token.Type {
+	case TypeBool:
+		if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return token.invalid(value, "must be a boolean")
+		}
+	case TypeInt:
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			return token.invalid(value, "must be an integer")
+		}
+	case TypeList:
+		for _, item := range splitList(value) {
+			if err := token.checkRules(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return token.checkRules(value)
+}
+
+func (token Token) invalid(value string, format string, args ...any) error {
+	return &TokenError{
+		Token: token.Name,
+		Err:   fmt.Errorf("%w %q: %s", ErrInvalidValue, value, fmt.Sprintf(format, args...)),
+	}
+}
+
+func (token Token) checkRules(value string) error {
+	invalid := func(format string, args ...any) error {
+		return token.invalid(value, format, args...)
+	}
+
+	if token.Pattern != "" {
+		pattern, err := regexp.Compile(token.Pattern)
+		if err != nil {
+			return &TokenError{Token: token.Name, Err: err}
+		}
+
+		if !pattern.MatchString(value) {
+			return invalid("must match pattern %s", token.Pattern)
+		}
+	}
+
+	length := utf8.RuneCountInString(value)
+
+	if token.MinLength > 0 && length < token.MinLength {
+		return invalid("must be at least %d characters", token.MinLength)
+	}
+
+	if token.MaxLength > 0 && length > token.MaxLength {
+		return invalid("must be at most %d characters", token.MaxLength)
+	}
+
+	if len(token.Choices) > 0 && !slices.Contains(token.Choices, value) {
+		return invalid("must be one of %s", strings.Join(token.Choices, "|"))
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateReportsAllMissingValues(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "{{name}}"
+
+		[[token]]
+		name = "{{Name}}"
+		token = "{{name}}"
+		modifiers = ["pascal"]
+
+		[[token]]
+		name = "{{module}}"
+
+		[[token]]
+		name = "{{static}}"
+		value = "static"
+
+		[[token]]
+		name = "{{optional}}"
+		required = false
+		modifiers = ["slug"]
+
+		[[token]]
+		name = "{{derived}}"
+		token = "{{optional}}"
+		required = true
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"main.go": "package {{name}}",
+	})
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	err = scaf.Validate()
+
+	missing := map[string]bool{}
+	for _, token := range []string{"{{name}}", "{{module}}", "{{derived}}"} {
+		missing[token] = false
+	}
+
+	for _, joined := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var tokenErr *TokenError
+		if !errors.As(joined, &tokenErr) || !errors.Is(tokenErr, ErrMissingValue) {
+			t.Errorf("Unexpected validation error: %v", joined)
+			continue
+		}
+
+		if _, ok := missing[tokenErr.Token]; !ok {
+			t.Errorf("Token %s should not be reported as missing", tokenErr.Token)
+		}
+		missing[tokenErr.Token] = true
+	}
+
+	for token, reported := range missing {
+		if !reported {
+			t.Errorf("Expected token %s to be reported as missing", token)
+		}
+	}
+
+	// Make must fail before touching the destination
+	destDir := filepath.Join(t.TempDir(), "output")
+
+	if err := scaf.Make(destDir); !errors.Is(err, ErrMissingValue) {
+		t.Errorf("Expected Make to fail with a missing value, got %v", err)
+	}
+
+	if _, err := os.Stat(destDir); !os.IsNotExist(err) {
+		t.Errorf("Make created the destination despite failing validation")
+	}
+
+	scaf.RegisterTokenValue("{{name}}", "app")
+	scaf.RegisterTokenValue("{{module}}", "example.com/app")
+	scaf.RegisterTokenValue("{{optional}}", "Some Value")
+
+	if err := scaf.Validate(); err != nil {
+		t.Errorf("Expected validation to pass, got %v", err)
+	}
+}
+
+func TestTokenValueRules(t *testing.T) {
+	configContent := `
+		[[token]]
+		name = "service"
+		pattern = "^[a-z][a-z0-9-]+$"
+		max_length = 12
+
+		[[token]]
+		name = "db"
+		choices = ["postgres", "mysql", "sqlite"]
+		default = "postgres"
+
+		[[token]]
+		name = "owner"
+		min_length = 3
+	`
+	templateDir := createTemplate(t, configContent, map[string]string{
+		"main.go": "service db owner",
+	})
+
+	scaf, err := Init(templateDir)
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	invalid := []struct {
+		token string
+		value string
+		rule  string
+	}{
+		{token: "service", value: "Billing", rule: `token "service": invalid value "Billing": must match pattern ^[a-z][a-z0-9-]+$`},
+		{token: "service", value: "billing-service", rule: `token "service": invalid value "billing-service": must be at most 12 characters`},
+		{token: "db", value: "oracle", rule: `token "db": invalid value "oracle": must be one of postgres|mysql|sqlite`},
+		{token: "owner", value: "al", rule: `token "owner": invalid value "al": must be at least 3 characters`},
+	}
+
+	for _, test := range invalid {
+		err := scaf.SetTokenValue(test.token, test.value)
+		if !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("Expected %q to be rejected for %s, got %v", test.value, test.token, err)
+			continue
+		}
+
+		if err.Error() != test.rule {
+			t.Errorf("Unexpected error message. Expected %q, got %q", test.rule, err.Error())
+		}
+	}
+
+	if len(scaf.TokenValueMap) != 0 {
+		t.Errorf("Rejected values should not be registered, got %v", scaf.TokenValueMap)
+	}
+
+	if err := scaf.SetTokenValue("missing", "value"); err == nil {
+		t.Errorf("Expected an error for an unknown token")
+	}
+
+	// Values registered without checks are still caught by Validate
+	scaf.RegisterTokenValue("service", "Billing")
+	scaf.RegisterTokenValue("owner", "platform")
+
+	if err := scaf.Validate(); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected Validate to reject the registered value, got %v", err)
+	}
+
+	if err := scaf.SetTokenValue("service", "billing"); err != nil {
+		t.Fatalf("Failed to set valid value: %v", err)
+	}
+
+	destDir := t.TempDir()
+
+	if err := scaf.Make(destDir); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	generatedContent, err := os.ReadFile(filepath.Join(destDir, "main.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	if string(generatedContent) != "billing postgres platform" {
+		t.Errorf("Unexpected generated content: %q", string(generatedContent))
+	}
+}
+
+func TestInvalidPatternInConfig(t *testing.T) {
+	_, err := parseConfig([]byte(`
+		[[token]]
+		name = "service"
+		pattern = "^[a-z"
+	`))
+	if err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
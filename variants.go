@@ -0,0 +1,186 @@
+package scaffold
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// caseVariants are the modifiers producing each case form of a token with
+// variants: camelCase, PascalCase, kebab-case, snake_case and UPPER_SNAKE.
+var caseVariants = [][]string{
+	{"camel"},
+	{"pascal"},
+	{"slug"},
+	{"snake"},
+	{"snake", "upper"},
+}
+
+// caseStyles are the modifiers producing every casing a token that
+// preserves case is recognized in: lower, UPPER, Title, camel, Pascal, snake,
+// kebab and UPPER_SNAKE.
+var caseStyles = [][]string{
+	{"lower"},
+	{"upper"},
+	{"title"},
+	{"camel"},
+	{"pascal"},
+	{"snake"},
+	{"slug"},
+	{"snake", "upper"},
+}
+
+// caseForm is a form of a token name along with the modifiers that turn the
+// value into the same form.
+type caseForm struct {
+	name      string
+	modifiers []string
+}
+
+// caseForms returns the forms of the name of a token with variants or that
+// preserves case. Variants map each form to the modifiers producing it, while
+// preserved forms map to the modifiers for the casing detected in the form.
+// Only the identifier part of the name changes, so delimiters such as the
+// braces of {{serviceName}} are kept.
+func (scaf *Scaffold) caseForms(token Token) []caseForm {
+	var forms []caseForm
+
+	prefix, identifier, suffix := splitName(token.Name)
+	if identifier == "" {
+		return nil
+	}
+
+	if token.Variants {
+		for _, modifiers := range caseVariants {
+			name := scaf.applyModifiers(modifiers, identifier)
+			forms = append(forms, caseForm{name: prefix + name + suffix, modifiers: modifiers})
+		}
+	}
+
+	if token.PreserveCase {
+		for _, modifiers := range caseStyles {
+			name := scaf.applyModifiers(modifiers, identifier)
+			forms = append(forms, caseForm{name: prefix + name + suffix, modifiers: detectCase(name)})
+		}
+	}
+
+	return forms
+}
+
+// splitName splits a token name into its leading and trailing characters
+// that are not letters or digits, and the identifier between them.
+func splitName(name string) (prefix string, identifier string, suffix string) {
+	isAlphanumeric := func(char rune) bool {
+		return unicode.IsLetter(char) || unicode.IsDigit(char)
+	}
+
+	start := strings.IndexFunc(name, isAlphanumeric)
+	if start < 0 {
+		return name, "", ""
+	}
+
+	end := strings.LastIndexFunc(name, isAlphanumeric)
+	_, size := utf8.DecodeRuneInString(name[end:])
+	end += size
+
+	return name[:start], name[start:end], name[end:]
+}
+
+// detectCase returns the modifiers producing the casing of an occurrence.
+// Title case is only detected in occurrences of several words.
+func detectCase(occurrence string) []string {
+	hasLower := strings.IndexFunc(occurrence, unicode.IsLower) >= 0
+	hasUpper := strings.IndexFunc(occurrence, unicode.IsUpper) >= 0
+
+	switch {
+	case strings.Contains(occurrence, "_") && !hasLower:
+		return []string{"snake", "upper"}
+	case strings.Contains(occurrence, "_"):
+		return []string{"snake"}
+	case strings.Contains(occurrence, "-"):
+		return []string{"slug"}
+	case !hasUpper:
+		return []string{"lower"}
+	case !hasLower:
+		return []string{"upper"}
+	case strings.Contains(occurrence, " "):
+		return []string{"title"}
+	}
+
+	// A capitalized word, like Service, is the PascalCase of a single word
+	if first, _ := utf8.DecodeRuneInString(occurrence); unicode.IsUpper(first) {
+		return []string{"pascal"}
+	}
+
+	return []string{"camel"}
+}
+
+// withVariants adds a token for every case form of the name of each token
+// with variants or that preserves case, replaced by the matching case form of
+// its value. The form the name is written in takes its case form too, and
+// forms that equal the name of another token are left to that token.
+func (scaf *Scaffold) withVariants(tokens []Token) []Token {
+	names := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		names[token.Name] = true
+	}
+
+	result := slices.Clone(tokens)
+
+	for i, token := range tokens {
+		ownForm := false
+
+		for _, form := range scaf.caseForms(token) {
+			value := scaf.caseValue(token, form.modifiers)
+
+			// A name like "service" is in several forms, the first one wins
+			if form.name == token.Name {
+				if !ownForm {
+					result[i].Value = value
+					ownForm = true
+				}
+
+				continue
+			}
+
+			if form.name == "" || names[form.name] {
+				continue
+			}
+
+			names[form.name] = true
+
+			variant := token
+			variant.Name = form.name
+			variant.Value = value
+			variant.Variants = false
+			variant.PreserveCase = false
+
+			result = append(result, variant)
+		}
+	}
+
+	return result
+}
+
+// caseValue applies case modifiers to the value of a token, or to every item
+// of a list.
+func (scaf *Scaffold) caseValue(token Token, modifiers []string) string {
+	items, ok := token.typed.([]string)
+	if !ok {
+		return scaf.applyModifiers(modifiers, token.Value)
+	}
+
+	forms := make([]string, len(items))
+	for i, item := range items {
+		forms[i] = scaf.applyModifiers(modifiers, item)
+	}
+
+	return strings.Join(forms, token.separator())
+}
+
+// replacerFor prepares the replacement of the resolved tokens, including
+// their case variants, with the configured delimiters.
+func (scaf *Scaffold) replacerFor(tokens []Token) *replacer {
+	return newReplacer(scaf.withVariants(tokens), scaf.Config.Delimiters)
+}
@@ -0,0 +1,173 @@
+package scaffold
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVariants(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "myService"
+			variants = true
+
+			[[token]]
+			name = "entities"
+			type = "list"
+			variants = true
+			separator = " "
+
+			# Declared tokens take precedence over variants
+			[[token]]
+			name = "MyService"
+			value = "Declared"
+		`)},
+		"cmd/my-service/main.go": &fstest.MapFile{Data: []byte(
+			"myService MyService my-service my_service MY_SERVICE\n" +
+				"entities Entities ENTITIES\n",
+		)},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("myService", "billing api")
+	scaf.RegisterTokenValue("entities", "user, line item")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	file, ok := out.Files["cmd/billing-api/main.go"]
+	if !ok {
+		t.Fatalf("Expected cmd/billing-api/main.go to be generated, got %v", out.Files)
+	}
+
+	expected := "billingApi Declared billing-api billing_api BILLING_API\n" +
+		"user lineItem User LineItem USER LINE_ITEM\n"
+	if string(file.Data) != expected {
+		t.Errorf("Unexpected content.\nExpected:\n%s\nGot:\n%s", expected, file.Data)
+	}
+}
+
+func TestVariantsWithDelimiters(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "{{serviceName}}"
+			variants = true
+		`)},
+		"main.go": &fstest.MapFile{Data: []byte(
+			"{{serviceName}} {{ServiceName}} {{service-name}} {{SERVICE_NAME}} serviceName ServiceName service-name\n",
+		)},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("{{serviceName}}", "orderItem")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	// Bare names are not tokens and must be left alone
+	expected := "orderItem OrderItem order-item ORDER_ITEM serviceName ServiceName service-name\n"
+	if string(out.Files["main.go"].Data) != expected {
+		t.Errorf("Unexpected content.\nExpected:\n%s\nGot:\n%s", expected, out.Files["main.go"].Data)
+	}
+}
+
+func TestDetectCase(t *testing.T) {
+	tests := map[string][]string{
+		"service":    {"lower"},
+		"SERVICE":    {"upper"},
+		"Service":    {"pascal"},
+		"My Service": {"title"},
+		"myService":  {"camel"},
+		"MyService":  {"pascal"},
+		"my_service": {"snake"},
+		"MY_SERVICE": {"snake", "upper"},
+		"my-service": {"slug"},
+		"myservice2": {"lower"},
+	}
+
+	for occurrence, expected := range tests {
+		if detected := detectCase(occurrence); !slices.Equal(detected, expected) {
+			t.Errorf("Unexpected case for %q. Expected %v, got %v", occurrence, expected, detected)
+		}
+	}
+}
+
+func TestPreserveCase(t *testing.T) {
+	templates := fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "myService"
+			preserve_case = true
+			match = "identifier"
+		`)},
+		"main.go": &fstest.MapFile{Data: []byte(
+			"myService MyService my_service MY_SERVICE my-service myservice MYSERVICE myServiceFoo\n",
+		)},
+	}
+
+	scaf, err := InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("myService", "orderItem")
+
+	out := NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected := "orderItem OrderItem order_item ORDER_ITEM order-item orderitem ORDERITEM myServiceFoo\n"
+	if string(out.Files["main.go"].Data) != expected {
+		t.Errorf("Unexpected content.\nExpected:\n%s\nGot:\n%s", expected, out.Files["main.go"].Data)
+	}
+	// A single word placeholder keeps producing valid identifiers
+	templates = fstest.MapFS{
+		"scaffold.toml": &fstest.MapFile{Data: []byte(`
+			[[token]]
+			name = "service"
+			preserve_case = true
+		`)},
+		"main.go": &fstest.MapFile{Data: []byte("type Service struct{}; var service Service; SERVICE\n")},
+	}
+
+	scaf, err = InitFS(templates, ".")
+	if err != nil {
+		t.Fatalf("Failed to init scaffold: %v", err)
+	}
+
+	scaf.AnswersFile = ""
+	scaf.RegisterTokenValue("service", "orderItem")
+
+	out = NewMemoryOutput()
+
+	if err := scaf.MakeTo(out); err != nil {
+		t.Fatalf("Failed to make scaffold: %v", err)
+	}
+
+	expected = "type OrderItem struct{}; var orderitem OrderItem; ORDERITEM\n"
+	if string(out.Files["main.go"].Data) != expected {
+		t.Errorf("Unexpected content.\nExpected:\n%s\nGot:\n%s", expected, out.Files["main.go"].Data)
+	}
+}